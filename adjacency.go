@@ -0,0 +1,129 @@
+package dot
+
+// storeEdge stores the edge in the innermost (sub)graph that contains both nodes and adds it to the index of the (root) graph.
+// Edges are kept in order of creation.
+func (g *Graph) storeEdge(e Edge) {
+	e.graph = commonParentOf(e.from.graph, e.to.graph)
+	e.graph.edgesFrom[e.from.id] = withEdge(e.graph.edgesFrom[e.from.id], e)
+	g.outIndex[e.from.seq] = withEdge(g.outIndex[e.from.seq], e)
+	g.inIndex[e.to.seq] = withEdge(g.inIndex[e.to.seq], e)
+}
+
+// unstoreEdge removes the edge from the (sub)graph in which it is stored and from the index of the (root) graph.
+func (g *Graph) unstoreEdge(e Edge) {
+	setOrDelete(e.graph.edgesFrom, e.from.id, withoutEdge(e.graph.edgesFrom[e.from.id], e))
+	setOrDeleteIndexed(g.outIndex, e.from.seq, withoutEdge(g.outIndex[e.from.seq], e))
+	setOrDeleteIndexed(g.inIndex, e.to.seq, withoutEdge(g.inIndex[e.to.seq], e))
+}
+
+// updateEdgesOf calls the function for each edge from or to the node in the (root) graph.
+// Edges for which the function returns false are removed ; the others are stored again,
+// see storeEdge, so that all copies of the edge in the (sub)graphs and the index have the (possibly changed) nodes.
+func (g *Graph) updateEdgesOf(n Node, f func(e Edge) (Edge, bool)) {
+	edges := append(append([]Edge{}, g.outIndex[n.seq]...), g.inIndex[n.seq]...)
+	done := map[int]bool{}
+	for _, each := range edges {
+		if done[each.seq] {
+			// a self loop is in both lists
+			continue
+		}
+		done[each.seq] = true
+		g.unstoreEdge(each)
+		if changed, keep := f(each); keep {
+			g.storeEdge(changed)
+		}
+	}
+}
+
+// withEdge returns the list with the edge inserted by its seq.
+func withEdge(list []Edge, e Edge) []Edge {
+	i := len(list)
+	for i > 0 && list[i-1].seq > e.seq {
+		i--
+	}
+	return append(list[:i], append([]Edge{e}, list[i:]...)...)
+}
+
+func withoutEdge(list []Edge, e Edge) []Edge {
+	kept := make([]Edge, 0, len(list))
+	for _, each := range list {
+		if !isSameEdge(each, e) {
+			kept = append(kept, each)
+		}
+	}
+	return kept
+}
+
+func setOrDelete(m map[string][]Edge, key string, list []Edge) {
+	if len(list) == 0 {
+		delete(m, key)
+		return
+	}
+	m[key] = list
+}
+
+func setOrDeleteIndexed(m map[int][]Edge, key int, list []Edge) {
+	if len(list) == 0 {
+		delete(m, key)
+		return
+	}
+	m[key] = list
+}
+
+// OutEdges returns the edges from this node, stored in any (sub)graph.
+func (n Node) OutEdges() []Edge {
+	return n.graph.OutEdges(n)
+}
+
+// InEdges returns the edges to this node, stored in any (sub)graph.
+func (n Node) InEdges() []Edge {
+	return n.graph.InEdges(n)
+}
+
+// OutEdges returns the edges from the node, stored in any (sub)graph, in order of creation.
+// For undirected graphs, these are the edges created with the node as the first argument.
+func (g *Graph) OutEdges(n Node) []Edge {
+	return append([]Edge{}, g.Root().outIndex[n.seq]...)
+}
+
+// InEdges returns the edges to the node, stored in any (sub)graph, in order of creation.
+// For undirected graphs, these are the edges created with the node as the second argument.
+func (g *Graph) InEdges(n Node) []Edge {
+	return append([]Edge{}, g.Root().inIndex[n.seq]...)
+}
+
+// OutDegree returns the number of edges from the node.
+func (g *Graph) OutDegree(n Node) int {
+	return len(g.Root().outIndex[n.seq])
+}
+
+// InDegree returns the number of edges to the node.
+func (g *Graph) InDegree(n Node) int {
+	return len(g.Root().inIndex[n.seq])
+}
+
+// Successors returns the distinct nodes that have an edge from the node, in order of edge creation.
+func (g *Graph) Successors(n Node) []Node {
+	nodes := []Node{}
+	seen := map[int]bool{}
+	for _, each := range g.Root().outIndex[n.seq] {
+		if !seen[each.to.seq] {
+			seen[each.to.seq] = true
+			nodes = append(nodes, each.to)
+		}
+	}
+	return nodes
+}
+
+// Predecessors returns the distinct nodes that have an edge to the node, in order of edge creation.
+func (g *Graph) Predecessors(n Node) []Node {
+	nodes := []Node{}
+	seen := map[int]bool{}
+	for _, each := range g.Root().inIndex[n.seq] {
+		if !seen[each.from.seq] {
+			seen[each.from.seq] = true
+			nodes = append(nodes, each.from)
+		}
+	}
+	return nodes
+}
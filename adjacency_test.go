@@ -0,0 +1,168 @@
+package dot
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestAdjacency(t *testing.T) {
+	di := NewGraph(Directed)
+	sub := di.Subgraph("sub")
+	a, b, c := di.Node("a"), sub.Node("b"), sub.Node("c")
+	a.Edge(b)
+	a.Edge(b)
+	b.Edge(c)
+	a.Edge(c)
+	if got, want := len(a.OutEdges()), 3; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := len(c.InEdges()), 2; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := sub.OutDegree(a), 3; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := di.InDegree(b), 2; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := nodeIDs(di.Successors(a)), "b c"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := nodeIDs(di.Predecessors(c)), "b a"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestAdjacencyAfterMutations(t *testing.T) {
+	di := NewGraph(Directed)
+	a, b, c := di.Node("a"), di.Node("b"), di.Node("c")
+	ab := a.Edge(b)
+	b.Edge(c)
+	c.Edge(a)
+	di.DeleteEdge(ab)
+	if got, want := di.OutDegree(a), 0; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	di.DeleteNode("c")
+	if got, want := di.InDegree(a)+di.OutDegree(b), 0; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	a.Edge(b)
+	z, _ := di.RenameNode(b, "z")
+	if got, want := nodeIDs(di.Successors(a)), "z"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := di.InDegree(z), 1; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	copied := di.DeepCopy()
+	ca, _ := copied.FindNodeById("a")
+	if got, want := copied.OutDegree(ca), 1; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestAdjacencyDuplicateIDs(t *testing.T) {
+	di := NewGraph(Directed)
+	one, two := di.Subgraph("one"), di.Subgraph("two")
+	x1, x2 := one.Node("x"), two.Node("x")
+	a := di.Node("a")
+	a.Edge(x1)
+	x2.Edge(a)
+	x2.Edge(x1)
+	if got, want := di.InDegree(x1)+di.OutDegree(x1), 2; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := di.InDegree(x2)+di.OutDegree(x2), 2; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := len(di.FindEdges(x2, x1)), 1; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	di.DeleteSubgraph(two)
+	if got, want := di.InDegree(x1), 1; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestAdjacencyOrderAfterMove(t *testing.T) {
+	di := NewGraph(Directed)
+	sub := di.Subgraph("sub")
+	a, b, c := di.Node("a"), di.Node("b"), di.Node("c")
+	a.Edge(b, "1")
+	a.Edge(c, "2")
+	a.Edge(b, "3")
+	di.MoveNode(b, sub)
+	labels := ""
+	for _, each := range a.OutEdges() {
+		labels += fmt.Sprint(each.Value("label"))
+		if each.To().ID() == "b" && each.To().Graph() != sub {
+			t.Error("indexed edge has a stale node")
+		}
+	}
+	if got, want := labels, "123"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func nodeIDs(nodes []Node) string {
+	s := ""
+	for i, each := range nodes {
+		if i > 0 {
+			s += " "
+		}
+		s += each.ID()
+	}
+	return s
+}
+
+// benchmarkGraph returns a graph with 10k nodes and 50k edges.
+func benchmarkGraph() *Graph {
+	g := NewGraph(Directed)
+	nodes := make([]Node, 10000)
+	for i := range nodes {
+		nodes[i] = g.Node(fmt.Sprintf("n%d", i))
+	}
+	for i := range nodes {
+		for j := 1; j <= 5; j++ {
+			g.Edge(nodes[i], nodes[(i+j*j*7)%len(nodes)])
+		}
+	}
+	return g
+}
+
+func BenchmarkPredecessorsIndexed(b *testing.B) {
+	g := benchmarkGraph()
+	n, _ := g.FindNodeById("n42")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.Predecessors(n)
+	}
+}
+
+// BenchmarkPredecessorsScan scans all edge lists, as needed without the incoming-edge index.
+func BenchmarkPredecessorsScan(b *testing.B) {
+	g := benchmarkGraph()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		found := []Node{}
+		for _, edges := range g.edgesFrom {
+			for _, e := range edges {
+				if e.to.id == "n42" {
+					found = append(found, e.from)
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkDeleteNode(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		g := benchmarkGraph()
+		b.StartTimer()
+		for j := 0; j < 100; j++ {
+			g.DeleteNode(fmt.Sprintf("n%d", j*37))
+		}
+	}
+}
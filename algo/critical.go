@@ -0,0 +1,130 @@
+package algo
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/eristocrates/dot"
+)
+
+// Schedule is the result of a critical path analysis. Times are keyed by node id ;
+// of nodes with the same id in different subgraphs, the map has the times of the last in topological order.
+type Schedule struct {
+	// Duration is the earliest time at which all nodes are finished.
+	Duration float64
+	// EarliestStart is the earliest time a node can start, after all its predecessors have finished.
+	EarliestStart map[string]float64
+	// LatestStart is the latest time a node can start without increasing the Duration.
+	LatestStart map[string]float64
+	// Slack is LatestStart minus EarliestStart ; nodes with zero slack are critical.
+	// Differences caused by floating point rounding are taken as zero.
+	Slack map[string]float64
+	// Critical has the edges between critical nodes where the second starts when the first finishes,
+	// in topological order of their From node.
+	Critical []dot.Edge
+}
+
+// nodeTimes has the times of one node, identified by its sequence number.
+type nodeTimes struct {
+	earliestStart, latestStart, slack float64
+}
+
+// CriticalPath computes the earliest and latest start and the slack of each node.
+// The duration of a node is read from the attribute with the given name ; nodes without a (numeric) value take no time.
+// Returns a *CycleError if the graph has a cycle and an error if a duration is negative.
+func CriticalPath(g *dot.Graph, durationAttribute string) (Schedule, error) {
+	s, _, err := criticalPath(g, durationAttribute)
+	return s, err
+}
+
+// criticalPath returns the Schedule and the times of each node by sequence number.
+func criticalPath(g *dot.Graph, durationAttribute string) (Schedule, map[int]*nodeTimes, error) {
+	sorted, err := TopologicalSort(g)
+	if err != nil {
+		return Schedule{}, nil, err
+	}
+	nodes := newGraphNodes(g)
+	s := Schedule{
+		EarliestStart: map[string]float64{},
+		LatestStart:   map[string]float64{},
+		Slack:         map[string]float64{},
+	}
+	times := map[int]*nodeTimes{}
+	duration := map[int]float64{}
+	for _, each := range sorted {
+		d := numberOf(each.Attribute(durationAttribute), 0)
+		if d < 0 {
+			return Schedule{}, nil, fmt.Errorf("negative %s %v of node %s", durationAttribute, d, each.ID())
+		}
+		duration[each.Seq()] = d
+		times[each.Seq()] = &nodeTimes{}
+	}
+	for _, each := range sorted {
+		t := times[each.Seq()]
+		for _, e := range nodes.inEdges(each) {
+			from := e.From().Seq()
+			t.earliestStart = max(t.earliestStart, times[from].earliestStart+duration[from])
+		}
+		s.Duration = max(s.Duration, t.earliestStart+duration[each.Seq()])
+	}
+	for i := len(sorted) - 1; i >= 0; i-- {
+		t := times[sorted[i].Seq()]
+		latestFinish := s.Duration
+		for _, e := range nodes.outEdges(sorted[i]) {
+			latestFinish = min(latestFinish, times[e.To().Seq()].latestStart)
+		}
+		t.latestStart = latestFinish - duration[sorted[i].Seq()]
+		t.slack = t.latestStart - t.earliestStart
+		if s.isZero(t.slack) {
+			t.slack = 0
+		}
+	}
+	for _, each := range sorted {
+		from := times[each.Seq()]
+		s.EarliestStart[each.ID()] = from.earliestStart
+		s.LatestStart[each.ID()] = from.latestStart
+		s.Slack[each.ID()] = from.slack
+		if from.slack != 0 {
+			continue
+		}
+		for _, e := range nodes.outEdges(each) {
+			to := times[e.To().Seq()]
+			if to.slack == 0 && s.isZero(from.earliestStart+duration[each.Seq()]-to.earliestStart) {
+				s.Critical = append(s.Critical, e)
+			}
+		}
+	}
+	return s, times, nil
+}
+
+// isZero returns whether the time is zero, allowing for rounding errors relative to the Duration.
+func (s Schedule) isZero(t float64) bool {
+	return math.Abs(t) <= 1e-9*max(1, s.Duration)
+}
+
+// HighlightCriticalPath computes the CriticalPath and writes the results into the graph.
+// The earliest and latest start and the slack of each node are added to its label ;
+// HTML and Literal labels are left as is. The critical edges are made bold and red.
+func HighlightCriticalPath(g *dot.Graph, durationAttribute string) (Schedule, error) {
+	s, times, err := criticalPath(g, durationAttribute)
+	if err != nil {
+		return s, err
+	}
+	for _, each := range newGraphNodes(g).sorted {
+		t := times[each.Seq()]
+		switch each.Attribute("label").(type) {
+		case string, nil:
+			es, ls, slack := formatTime(t.earliestStart), formatTime(t.latestStart), formatTime(t.slack)
+			each.Label(fmt.Sprintf("%s\nES %s LS %s slack %s", memberLabel(each), es, ls, slack))
+		}
+	}
+	for _, each := range s.Critical {
+		each.Bold().SetAttribute("color", "red")
+	}
+	return s, nil
+}
+
+func formatTime(t float64) string {
+	return strconv.FormatFloat(t, 'f', -1, 64)
+}
@@ -0,0 +1,108 @@
+package algo
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/eristocrates/dot"
+)
+
+// pipeline returns a graph with: compile(3) -> test(5) -> deploy(1) and compile -> lint(1) -> deploy.
+func pipeline() *dot.Graph {
+	g := dot.NewGraph(dot.Directed)
+	compile := g.Node("compile").SetAttribute("duration", "3")
+	test := g.Node("test").SetAttribute("duration", 5)
+	lint := g.Node("lint").SetAttribute("duration", "1.5")
+	deploy := g.Node("deploy").SetAttribute("duration", "1")
+	compile.Edge(test)
+	compile.Edge(lint)
+	test.Edge(deploy)
+	lint.Edge(deploy)
+	return g
+}
+
+func TestCriticalPath(t *testing.T) {
+	s, err := CriticalPath(pipeline(), "duration")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := s.Duration, 9.0; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := s.EarliestStart["deploy"], 8.0; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := s.LatestStart["lint"], 6.5; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := s.Slack["lint"], 3.5; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := edgePath(s.Critical), "compile->test test->deploy"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestHighlightCriticalPath(t *testing.T) {
+	g := pipeline()
+	if _, err := HighlightCriticalPath(g, "duration"); err != nil {
+		t.Fatal(err)
+	}
+	lint, _ := g.FindNodeById("lint")
+	if got, want := lint.Attribute("label"), "lint\nES 3 LS 6.5 slack 3.5"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := lint.Attribute("slack"), interface{}(nil); got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	compile, _ := g.FindNodeById("compile")
+	test, _ := g.FindNodeById("test")
+	e := g.FindEdges(compile, test)[0]
+	if got, want := e.Attribute("color"), "red"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := g.FindEdges(compile, lint)[0].Attribute("color"), interface{}(nil); got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestCriticalPathRounding(t *testing.T) {
+	g := dot.NewGraph(dot.Directed)
+	a := g.Node("a").SetAttribute("duration", "0.1")
+	b := g.Node("b").SetAttribute("duration", "0.2")
+	c := g.Node("c").SetAttribute("duration", "0.3")
+	d := g.Node("d").SetAttribute("label", dot.HTML("<B>d</B>"))
+	a.Edge(b)
+	b.Edge(d)
+	c.Edge(d)
+	s, err := HighlightCriticalPath(g, "duration")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := s.Slack["c"], 0.0; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := edgePath(s.Critical), "a->b b->d c->d"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := d.Attribute("label"), interface{}(dot.HTML("<B>d</B>")); got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestCriticalPathErrors(t *testing.T) {
+	g := pipeline()
+	deploy, _ := g.FindNodeById("deploy")
+	compile, _ := g.FindNodeById("compile")
+	deploy.Edge(compile)
+	_, err := CriticalPath(g, "duration")
+	var cerr *CycleError
+	if !errors.As(err, &cerr) {
+		t.Errorf("expected CycleError, got %v", err)
+	}
+	negative := dot.NewGraph(dot.Directed)
+	negative.Node("a").SetAttribute("duration", "-1")
+	if _, err := CriticalPath(negative, "duration"); err == nil {
+		t.Error("expected error for negative duration")
+	}
+}
@@ -0,0 +1,129 @@
+package algo
+
+import (
+	"sort"
+
+	"github.com/eristocrates/dot"
+)
+
+// FindCycles returns a cycle, as a path of edges, for each back edge found by a depth-first search.
+// Each cycle starts and ends at the same node. Self-loops are cycles with one edge.
+// Returns an empty list if the graph has no cycles.
+func FindCycles(g *dot.Graph) [][]dot.Edge {
+	nodes := newGraphNodes(g)
+	const (
+		unvisited = iota
+		onPath
+		done
+	)
+	state := map[int]int{}
+	// pathIndex has the position in path of the first edge from a node on the path
+	pathIndex := map[int]int{}
+	path := []dot.Edge{}
+	cycles := [][]dot.Edge{}
+	var visit func(n dot.Node)
+	visit = func(n dot.Node) {
+		state[n.Seq()] = onPath
+		pathIndex[n.Seq()] = len(path)
+		for _, each := range nodes.outEdges(n) {
+			to := each.To()
+			switch state[to.Seq()] {
+			case onPath:
+				cycle := append([]dot.Edge{}, path[pathIndex[to.Seq()]:]...)
+				cycles = append(cycles, append(cycle, each))
+			case unvisited:
+				path = append(path, each)
+				visit(to)
+				path = path[:len(path)-1]
+			}
+		}
+		state[n.Seq()] = done
+	}
+	for _, each := range nodes.sorted {
+		if state[each.Seq()] == unvisited {
+			visit(each)
+		}
+	}
+	return cycles
+}
+
+// HasCycle returns whether the graph has at least one cycle.
+func HasCycle(g *dot.Graph) bool {
+	return len(FindCycles(g)) > 0
+}
+
+// StronglyConnectedComponents returns the strongly connected components using Tarjan's algorithm.
+// Components are returned in reverse topological order ; the nodes of each component are sorted by id.
+func StronglyConnectedComponents(g *dot.Graph) [][]dot.Node {
+	nodes := newGraphNodes(g)
+	index := 0
+	indices := map[int]int{}
+	lowlinks := map[int]int{}
+	onStack := map[int]bool{}
+	stack := []dot.Node{}
+	components := [][]dot.Node{}
+	var connect func(n dot.Node)
+	connect = func(n dot.Node) {
+		id := n.Seq()
+		indices[id] = index
+		lowlinks[id] = index
+		index++
+		stack = append(stack, n)
+		onStack[id] = true
+		for _, each := range nodes.outEdges(n) {
+			to := each.To().Seq()
+			if _, visited := indices[to]; !visited {
+				connect(nodes.bySeq[to])
+				lowlinks[id] = min(lowlinks[id], lowlinks[to])
+			} else if onStack[to] {
+				lowlinks[id] = min(lowlinks[id], indices[to])
+			}
+		}
+		if lowlinks[id] != indices[id] {
+			return
+		}
+		component := []dot.Node{}
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top.Seq()] = false
+			component = append(component, top)
+			if top.Seq() == id {
+				break
+			}
+		}
+		sort.Slice(component, func(i, j int) bool { return lessNode(component[i], component[j]) })
+		components = append(components, component)
+	}
+	for _, each := range nodes.sorted {
+		if _, visited := indices[each.Seq()]; !visited {
+			connect(each)
+		}
+	}
+	return components
+}
+
+// HighlightCycles sets the attribute "color" to "red" on every edge that is part of a cycle,
+// i.e. each edge between nodes of the same strongly connected component and each self-loop.
+// Returns the highlighted edges.
+func HighlightCycles(g *dot.Graph) []dot.Edge {
+	nodes := newGraphNodes(g)
+	componentOf := map[int]int{}
+	components := StronglyConnectedComponents(g)
+	for i, each := range components {
+		for _, n := range each {
+			componentOf[n.Seq()] = i
+		}
+	}
+	highlighted := []dot.Edge{}
+	for _, n := range nodes.sorted {
+		c := componentOf[n.Seq()]
+		for _, each := range nodes.outEdges(n) {
+			to := each.To().Seq()
+			if componentOf[to] == c && (to == n.Seq() || len(components[c]) > 1) {
+				highlighted = append(highlighted, each.SetAttribute("color", "red"))
+			}
+		}
+	}
+	return highlighted
+}
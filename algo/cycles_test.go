@@ -0,0 +1,71 @@
+package algo
+
+import (
+	"testing"
+
+	"github.com/eristocrates/dot"
+)
+
+func cyclicGraph() *dot.Graph {
+	g := dot.NewGraph(dot.Directed)
+	sub := g.Subgraph("sub")
+	a, b, c := g.Node("a"), sub.Node("b"), sub.Node("c")
+	d, e := g.Node("d"), g.Node("e")
+	a.Edge(b)
+	b.Edge(c)
+	c.Edge(a)
+	c.Edge(d)
+	d.Edge(e)
+	e.Edge(e)
+	return g
+}
+
+func TestFindCycles(t *testing.T) {
+	cycles := FindCycles(cyclicGraph())
+	if got, want := len(cycles), 2; got != want {
+		t.Fatalf("got [%v] want [%v]", got, want)
+	}
+	if got, want := edgePath(cycles[0]), "a->b b->c c->a"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := edgePath(cycles[1]), "e->e"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if HasCycle(dot.NewGraph(dot.Directed)) {
+		t.Error("empty graph has no cycle")
+	}
+}
+
+func TestStronglyConnectedComponents(t *testing.T) {
+	components := StronglyConnectedComponents(cyclicGraph())
+	list := []string{}
+	for _, each := range components {
+		list = append(list, ids(each))
+	}
+	if got, want := len(list), 3; got != want {
+		t.Fatalf("got [%v] want [%v]", got, want)
+	}
+	for i, want := range []string{"e", "d", "a b c"} {
+		if got := list[i]; got != want {
+			t.Errorf("got [%v] want [%v]", got, want)
+		}
+	}
+}
+
+func TestHighlightCycles(t *testing.T) {
+	g := cyclicGraph()
+	edges := HighlightCycles(g)
+	if got, want := edgePath(edges), "a->b b->c c->a e->e"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	c, _ := g.FindNodeById("c")
+	d, _ := g.FindNodeById("d")
+	if got := g.FindEdges(c, d)[0].Value("color"); got != nil {
+		t.Errorf("got [%v] want [nil]", got)
+	}
+	a, _ := g.FindNodeById("a")
+	b, _ := g.FindNodeById("b")
+	if got, want := g.FindEdges(a, b)[0].Value("color"), "red"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
@@ -0,0 +1,4 @@
+// Package algo provides graph algorithms that operate on a *dot.Graph, including all its subgraphs.
+// Edges are followed from their From node to their To node, also in undirected graphs.
+// Results are deterministic: nodes are visited in order of their id and edges in order of creation.
+package algo // import "github.com/eristocrates/dot/algo"
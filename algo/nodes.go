@@ -0,0 +1,55 @@
+package algo
+
+import (
+	"sort"
+
+	"github.com/eristocrates/dot"
+)
+
+// graphNodes has the nodes of a graph (and its subgraphs) sorted by id.
+// Nodes are identified by their sequence number because subgraphs can have nodes with the same id.
+type graphNodes struct {
+	graph  *dot.Graph
+	sorted []dot.Node
+	bySeq  map[int]dot.Node
+}
+
+func newGraphNodes(g *dot.Graph) graphNodes {
+	all := g.FindNodes()
+	sort.Slice(all, func(i, j int) bool { return lessNode(all[i], all[j]) })
+	bySeq := make(map[int]dot.Node, len(all))
+	for _, each := range all {
+		bySeq[each.Seq()] = each
+	}
+	return graphNodes{graph: g, sorted: all, bySeq: bySeq}
+}
+
+// lessNode orders nodes by id and then, for nodes with the same id, by sequence number.
+func lessNode(one, two dot.Node) bool {
+	if one.ID() != two.ID() {
+		return one.ID() < two.ID()
+	}
+	return one.Seq() < two.Seq()
+}
+
+// outEdges returns the edges from the node to nodes of the graph.
+func (n graphNodes) outEdges(from dot.Node) []dot.Edge {
+	edges := []dot.Edge{}
+	for _, each := range n.graph.OutEdges(from) {
+		if _, ok := n.bySeq[each.To().Seq()]; ok {
+			edges = append(edges, each)
+		}
+	}
+	return edges
+}
+
+// inEdges returns the edges to the node from nodes of the graph.
+func (n graphNodes) inEdges(to dot.Node) []dot.Edge {
+	edges := []dot.Edge{}
+	for _, each := range n.graph.InEdges(to) {
+		if _, ok := n.bySeq[each.From().Seq()]; ok {
+			edges = append(edges, each)
+		}
+	}
+	return edges
+}
@@ -0,0 +1,266 @@
+package algo
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/eristocrates/dot"
+)
+
+// ErrNoPath is returned if there is no path between two nodes.
+var ErrNoPath = errors.New("no path")
+
+// BreadthFirst visits the nodes reachable from start (including start) in breadth-first order.
+// Visiting stops when the callback returns false.
+func BreadthFirst(g *dot.Graph, start dot.Node, visit func(n dot.Node) bool) {
+	nodes := newGraphNodes(g)
+	seen := map[int]bool{start.Seq(): true}
+	queue := []dot.Node{start}
+	for len(queue) > 0 {
+		next := queue[0]
+		queue = queue[1:]
+		if !visit(next) {
+			return
+		}
+		for _, each := range nodes.outEdges(next) {
+			if to := each.To(); !seen[to.Seq()] {
+				seen[to.Seq()] = true
+				queue = append(queue, to)
+			}
+		}
+	}
+}
+
+// DepthFirst visits the nodes reachable from start (including start) in depth-first (pre)order.
+// Visiting stops when the callback returns false.
+func DepthFirst(g *dot.Graph, start dot.Node, visit func(n dot.Node) bool) {
+	nodes := newGraphNodes(g)
+	seen := map[int]bool{}
+	var walk func(n dot.Node) bool
+	walk = func(n dot.Node) bool {
+		seen[n.Seq()] = true
+		if !visit(n) {
+			return false
+		}
+		for _, each := range nodes.outEdges(n) {
+			if to := each.To(); !seen[to.Seq()] {
+				if !walk(to) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	walk(start)
+}
+
+// Reachable returns whether there is a path from one node to another.
+// A node is reachable from itself.
+func Reachable(g *dot.Graph, from, to dot.Node) bool {
+	found := false
+	BreadthFirst(g, from, func(n dot.Node) bool {
+		found = n.Seq() == to.Seq()
+		return !found
+	})
+	return found
+}
+
+// Descendants returns the nodes that can be reached from the node, sorted by id.
+// The node itself is only included if it is part of a cycle.
+func Descendants(g *dot.Graph, n dot.Node) []dot.Node {
+	nodes := newGraphNodes(g)
+	return reach(n, func(each dot.Node) []dot.Node {
+		next := []dot.Node{}
+		for _, e := range nodes.outEdges(each) {
+			next = append(next, e.To())
+		}
+		return next
+	})
+}
+
+// Ancestors returns the nodes from which the node can be reached, sorted by id.
+// The node itself is only included if it is part of a cycle.
+func Ancestors(g *dot.Graph, n dot.Node) []dot.Node {
+	nodes := newGraphNodes(g)
+	return reach(n, func(each dot.Node) []dot.Node {
+		next := []dot.Node{}
+		for _, e := range nodes.inEdges(each) {
+			next = append(next, e.From())
+		}
+		return next
+	})
+}
+
+func reach(start dot.Node, next func(dot.Node) []dot.Node) []dot.Node {
+	seen := map[int]bool{}
+	found := []dot.Node{}
+	queue := []dot.Node{start}
+	for len(queue) > 0 {
+		each := queue[0]
+		queue = queue[1:]
+		for _, other := range next(each) {
+			if !seen[other.Seq()] {
+				seen[other.Seq()] = true
+				found = append(found, other)
+				queue = append(queue, other)
+			}
+		}
+	}
+	sort.Slice(found, func(i, j int) bool { return lessNode(found[i], found[j]) })
+	return found
+}
+
+// ShortestPath returns a path with the fewest edges from one node to another.
+// Returns an empty path if both nodes are the same and ErrNoPath if there is no path.
+func ShortestPath(g *dot.Graph, from, to dot.Node) ([]dot.Edge, error) {
+	path, _, err := WeightedShortestPath(g, from, to, func(dot.Edge) float64 { return 1 })
+	return path, err
+}
+
+// WeightFunc returns the weight of an edge.
+type WeightFunc func(e dot.Edge) float64
+
+// AttributeWeight returns a WeightFunc that reads a numeric edge attribute such as "weight".
+// The defaultWeight is used for edges without a (numeric) value.
+func AttributeWeight(name string, defaultWeight float64) WeightFunc {
+	return func(e dot.Edge) float64 {
+		return numberOf(e.Attribute(name), defaultWeight)
+	}
+}
+
+// numberOf returns the attribute value as a number, or the default if it is not numeric.
+// Literal values, such as those of a parsed graph, are read without their enclosing quotes.
+func numberOf(value interface{}, defaultNumber float64) float64 {
+	switch v := value.(type) {
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case float32:
+		return float64(v)
+	case float64:
+		return v
+	case string:
+		if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+			return f
+		}
+	case dot.Literal:
+		text := strings.TrimSpace(string(v))
+		if len(text) >= 2 && strings.HasPrefix(text, `"`) && strings.HasSuffix(text, `"`) {
+			text = text[1 : len(text)-1]
+		}
+		if f, err := strconv.ParseFloat(strings.TrimSpace(text), 64); err == nil {
+			return f
+		}
+	}
+	return defaultNumber
+}
+
+// WeightedShortestPath returns a path from one node to another with the lowest total weight, using Dijkstra's algorithm.
+// Returns ErrNoPath if there is no path and an error if an edge has a negative weight.
+func WeightedShortestPath(g *dot.Graph, from, to dot.Node, weight WeightFunc) ([]dot.Edge, float64, error) {
+	nodes := newGraphNodes(g)
+	distance := map[int]float64{from.Seq(): 0}
+	via := map[int]dot.Edge{}
+	done := map[int]bool{}
+	queue := &distanceHeap{{node: from}}
+	for queue.Len() > 0 {
+		next := heap.Pop(queue).(distanceItem)
+		id := next.node.Seq()
+		if done[id] {
+			continue
+		}
+		done[id] = true
+		if id == to.Seq() {
+			break
+		}
+		for _, each := range nodes.outEdges(next.node) {
+			w := weight(each)
+			if w < 0 {
+				return nil, 0, fmt.Errorf("negative weight %v on edge %s -> %s", w, next.node.ID(), each.To().ID())
+			}
+			other := each.To().Seq()
+			if d, ok := distance[other]; !ok || next.distance+w < d {
+				distance[other] = next.distance + w
+				via[other] = each
+				heap.Push(queue, distanceItem{node: each.To(), distance: next.distance + w})
+			}
+		}
+	}
+	if !done[to.Seq()] {
+		return nil, 0, ErrNoPath
+	}
+	path := []dot.Edge{}
+	for id := to.Seq(); id != from.Seq(); {
+		e := via[id]
+		path = append([]dot.Edge{e}, path...)
+		id = e.From().Seq()
+	}
+	return path, distance[to.Seq()], nil
+}
+
+type distanceItem struct {
+	node     dot.Node
+	distance float64
+}
+
+// distanceHeap is a min-heap by distance, then by node id.
+type distanceHeap []distanceItem
+
+func (h distanceHeap) Len() int { return len(h) }
+func (h distanceHeap) Less(i, j int) bool {
+	if h[i].distance != h[j].distance {
+		return h[i].distance < h[j].distance
+	}
+	return lessNode(h[i].node, h[j].node)
+}
+func (h distanceHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *distanceHeap) Push(x interface{}) { *h = append(*h, x.(distanceItem)) }
+func (h *distanceHeap) Pop() interface{} {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
+
+// AllSimplePaths returns the paths from one node to another that visit each node at most once.
+// At most limit paths are returned ; use 0 (or less) for no limit.
+func AllSimplePaths(g *dot.Graph, from, to dot.Node, limit int) [][]dot.Edge {
+	nodes := newGraphNodes(g)
+	paths := [][]dot.Edge{}
+	onPath := map[int]bool{from.Seq(): true}
+	path := []dot.Edge{}
+	var walk func(n dot.Node) bool
+	walk = func(n dot.Node) bool {
+		for _, each := range nodes.outEdges(n) {
+			other := each.To().Seq()
+			if other == to.Seq() {
+				paths = append(paths, append(append([]dot.Edge{}, path...), each))
+				if limit > 0 && len(paths) == limit {
+					return false
+				}
+				continue
+			}
+			if onPath[other] {
+				continue
+			}
+			onPath[other] = true
+			path = append(path, each)
+			more := walk(each.To())
+			path = path[:len(path)-1]
+			onPath[other] = false
+			if !more {
+				return false
+			}
+		}
+		return true
+	}
+	if from.Seq() != to.Seq() {
+		walk(from)
+	}
+	return paths
+}
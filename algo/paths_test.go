@@ -0,0 +1,138 @@
+package algo
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/eristocrates/dot"
+)
+
+// pathGraph returns a -> b -> d, a -> c -> d, d -> e and a separate node f.
+func pathGraph() *dot.Graph {
+	g := dot.NewGraph(dot.Directed)
+	sub := g.Subgraph("sub")
+	a, b, c := g.Node("a"), sub.Node("b"), sub.Node("c")
+	d, e := g.Node("d"), g.Node("e")
+	g.Node("f")
+	a.Edge(b).SetAttribute("weight", "5")
+	a.Edge(c).SetAttribute("weight", "1")
+	b.Edge(d).SetAttribute("weight", "1")
+	c.Edge(d).SetAttribute("weight", "2")
+	d.Edge(e)
+	return g
+}
+
+func node(g *dot.Graph, id string) dot.Node {
+	n, _ := g.FindNodeById(id)
+	return n
+}
+
+func TestBreadthFirst(t *testing.T) {
+	g := pathGraph()
+	visited := []dot.Node{}
+	BreadthFirst(g, node(g, "a"), func(n dot.Node) bool {
+		visited = append(visited, n)
+		return true
+	})
+	if got, want := ids(visited), "a b c d e"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestDepthFirstStop(t *testing.T) {
+	g := pathGraph()
+	visited := []dot.Node{}
+	DepthFirst(g, node(g, "a"), func(n dot.Node) bool {
+		visited = append(visited, n)
+		return n.ID() != "d"
+	})
+	if got, want := ids(visited), "a b d"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestReachable(t *testing.T) {
+	g := pathGraph()
+	if !Reachable(g, node(g, "c"), node(g, "e")) {
+		t.Error("expected e reachable from c")
+	}
+	if Reachable(g, node(g, "e"), node(g, "a")) {
+		t.Error("expected a not reachable from e")
+	}
+}
+
+func TestAncestorsDescendants(t *testing.T) {
+	g := pathGraph()
+	if got, want := ids(Descendants(g, node(g, "b"))), "d e"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := ids(Ancestors(g, node(g, "d"))), "a b c"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestShortestPath(t *testing.T) {
+	g := pathGraph()
+	path, err := ShortestPath(g, node(g, "a"), node(g, "e"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := edgePath(path), "a->b b->d d->e"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if _, err := ShortestPath(g, node(g, "a"), node(g, "f")); !errors.Is(err, ErrNoPath) {
+		t.Errorf("expected ErrNoPath, got %v", err)
+	}
+}
+
+func TestAttributeWeightLiteral(t *testing.T) {
+	g := dot.NewGraph(dot.Directed)
+	a, b, c := g.Node("a"), g.Node("b"), g.Node("c")
+	a.Edge(b).SetAttribute("weight", dot.Literal("5"))
+	a.Edge(c).SetAttribute("weight", dot.Literal(`"1.5"`))
+	c.Edge(b).SetAttribute("weight", " 1")
+	path, total, err := WeightedShortestPath(g, a, b, AttributeWeight("weight", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := edgePath(path), "a->c c->b"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := total, 2.5; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestWeightedShortestPath(t *testing.T) {
+	g := pathGraph()
+	path, total, err := WeightedShortestPath(g, node(g, "a"), node(g, "e"), AttributeWeight("weight", 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := edgePath(path), "a->c c->d d->e"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := total, 4.0; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	negative := func(e dot.Edge) float64 { return -1 }
+	if _, _, err := WeightedShortestPath(g, node(g, "a"), node(g, "e"), negative); err == nil {
+		t.Error("expected error for negative weight")
+	}
+}
+
+func TestAllSimplePaths(t *testing.T) {
+	g := pathGraph()
+	d := node(g, "d")
+	d.Edge(node(g, "a"))
+	paths := AllSimplePaths(g, node(g, "a"), node(g, "e"), 0)
+	if got, want := len(paths), 2; got != want {
+		t.Fatalf("got [%v] want [%v]", got, want)
+	}
+	if got, want := edgePath(paths[1]), "a->c c->d d->e"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := len(AllSimplePaths(g, node(g, "a"), node(g, "e"), 1)), 1; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
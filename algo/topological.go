@@ -0,0 +1,75 @@
+package algo
+
+import (
+	"container/heap"
+	"fmt"
+	"strings"
+
+	"github.com/eristocrates/dot"
+)
+
+// CycleError is returned if an algorithm requires a graph without cycles.
+type CycleError struct {
+	// Cycle has the edges of one cycle in the graph.
+	Cycle []dot.Edge
+}
+
+func (e *CycleError) Error() string {
+	ids := []string{}
+	for _, each := range e.Cycle {
+		ids = append(ids, each.From().ID())
+	}
+	if len(e.Cycle) > 0 {
+		ids = append(ids, e.Cycle[len(e.Cycle)-1].To().ID())
+	}
+	return fmt.Sprintf("graph has a cycle: %s", strings.Join(ids, " -> "))
+}
+
+// TopologicalSort returns all nodes such that for each edge its From node comes before its To node.
+// Of the nodes that can be next, the one with the smallest id is taken.
+// Returns a *CycleError if the graph has a cycle.
+func TopologicalSort(g *dot.Graph) ([]dot.Node, error) {
+	nodes := newGraphNodes(g)
+	inDegree := map[int]int{}
+	ready := &idHeap{}
+	for _, each := range nodes.sorted {
+		inDegree[each.Seq()] = len(nodes.inEdges(each))
+		if inDegree[each.Seq()] == 0 {
+			heap.Push(ready, each)
+		}
+	}
+	sorted := []dot.Node{}
+	for ready.Len() > 0 {
+		next := heap.Pop(ready).(dot.Node)
+		sorted = append(sorted, next)
+		for _, each := range nodes.outEdges(next) {
+			to := each.To().Seq()
+			inDegree[to]--
+			if inDegree[to] == 0 {
+				heap.Push(ready, nodes.bySeq[to])
+			}
+		}
+	}
+	if len(sorted) < len(nodes.sorted) {
+		err := &CycleError{}
+		if cycles := FindCycles(g); len(cycles) > 0 {
+			err.Cycle = cycles[0]
+		}
+		return sorted, err
+	}
+	return sorted, nil
+}
+
+// idHeap is a min-heap of nodes by id ; nodes with the same id by sequence number.
+type idHeap []dot.Node
+
+func (h idHeap) Len() int            { return len(h) }
+func (h idHeap) Less(i, j int) bool  { return lessNode(h[i], h[j]) }
+func (h idHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *idHeap) Push(x interface{}) { *h = append(*h, x.(dot.Node)) }
+func (h *idHeap) Pop() interface{} {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
@@ -0,0 +1,109 @@
+package algo
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/eristocrates/dot"
+)
+
+func ids(nodes []dot.Node) string {
+	list := []string{}
+	for _, each := range nodes {
+		list = append(list, each.ID())
+	}
+	return strings.Join(list, " ")
+}
+
+func edgePath(edges []dot.Edge) string {
+	list := []string{}
+	for _, each := range edges {
+		list = append(list, each.From().ID()+"->"+each.To().ID())
+	}
+	return strings.Join(list, " ")
+}
+
+func TestTopologicalSort(t *testing.T) {
+	g := dot.NewGraph(dot.Directed)
+	sub := g.Subgraph("sub")
+	d := sub.Node("d")
+	c := g.Node("c")
+	b := g.Node("b")
+	a := sub.Node("a")
+	e := g.Node("e")
+	c.Edge(d)
+	a.Edge(d)
+	d.Edge(e)
+	b.Edge(e)
+	sorted, err := TopologicalSort(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ids(sorted), "a b c d e"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestTopologicalSortCycle(t *testing.T) {
+	g := dot.NewGraph(dot.Directed)
+	a, b, c := g.Node("a"), g.Node("b"), g.Node("c")
+	a.Edge(b)
+	b.Edge(c)
+	c.Edge(b)
+	_, err := TopologicalSort(g)
+	var cerr *CycleError
+	if !errors.As(err, &cerr) {
+		t.Fatalf("expected CycleError, got %v", err)
+	}
+	if got, want := err.Error(), "graph has a cycle: b -> c -> b"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestTopologicalSortSubgraph(t *testing.T) {
+	g := dot.NewGraph(dot.Directed)
+	sub := g.Subgraph("sub")
+	x, y := sub.Node("x"), sub.Node("y")
+	outside := g.Node("outside")
+	y.Edge(x)
+	x.Edge(outside)
+	outside.Edge(y)
+	// only nodes and edges of the subgraph are considered
+	sorted, err := TopologicalSort(sub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ids(sorted), "y x"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestTopologicalSortDuplicateIDs(t *testing.T) {
+	g := dot.NewGraph(dot.Directed)
+	a := g.Node("a")
+	one := g.Subgraph("one").Node("x")
+	two := g.Subgraph("two").Node("x")
+	a.Edge(one)
+	for i := 0; i < 20; i++ {
+		sorted, err := TopologicalSort(g)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := ids(sorted), "a x x"; got != want {
+			t.Errorf("got [%v] want [%v]", got, want)
+		}
+		if got, want := sorted[1].Seq(), one.Seq(); got != want {
+			t.Errorf("got [%v] want [%v]", got, want)
+		}
+	}
+	if HasCycle(g) {
+		t.Error("unexpected cycle")
+	}
+	if !Reachable(g, a, one) || Reachable(g, a, two) {
+		t.Error("expected only x of subgraph one to be reachable")
+	}
+	if _, err := ShortestPath(g, a, two); err != ErrNoPath {
+		t.Errorf("got [%v] want [%v]", err, ErrNoPath)
+	}
+}
@@ -0,0 +1,137 @@
+package algo
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/eristocrates/dot"
+)
+
+// TransformOption changes how a transform creates its new graph.
+type TransformOption interface {
+	apply(*transform)
+}
+
+// KeepRemovedEdges makes a transform keep the edges it removes, with style=invis,
+// so that the layout of the result stays close to the layout of the original.
+type KeepRemovedEdges struct{}
+
+func (o KeepRemovedEdges) apply(t *transform) {
+	t.keepRemoved = true
+}
+
+type transform struct {
+	graph       *dot.Graph
+	keepRemoved bool
+}
+
+func newTransform(g *dot.Graph, options []TransformOption) *transform {
+	t := &transform{graph: g.DeepCopy()}
+	for _, each := range options {
+		each.apply(t)
+	}
+	return t
+}
+
+// remove deletes the edge or makes it invisible.
+func (t *transform) remove(e dot.Edge) {
+	if t.keepRemoved {
+		e.SetAttribute("style", "invis")
+		return
+	}
+	t.graph.DeleteEdge(e)
+}
+
+// TransitiveReduction returns a copy of the graph without redundant edges.
+// An edge a -> c is redundant if c can also be reached from a through other nodes ; of parallel edges only the first is kept.
+// Returns a *CycleError if the graph has a cycle, because then the reduction is not unique.
+func TransitiveReduction(g *dot.Graph, options ...TransformOption) (*dot.Graph, error) {
+	t := newTransform(g, options)
+	sorted, err := TopologicalSort(t.graph)
+	if err != nil {
+		return nil, err
+	}
+	nodes := newGraphNodes(t.graph)
+	// descendants per node, computed in reverse topological order
+	descendants := map[int]map[int]bool{}
+	for i := len(sorted) - 1; i >= 0; i-- {
+		reached := map[int]bool{}
+		for _, each := range nodes.outEdges(sorted[i]) {
+			to := each.To().Seq()
+			reached[to] = true
+			for id := range descendants[to] {
+				reached[id] = true
+			}
+		}
+		descendants[sorted[i].Seq()] = reached
+	}
+	for _, n := range sorted {
+		edges := nodes.outEdges(n)
+		kept := map[int]bool{}
+		for _, each := range edges {
+			to := each.To().Seq()
+			if kept[to] || reachedThroughOther(edges, to, descendants) {
+				t.remove(each)
+				continue
+			}
+			kept[to] = true
+		}
+	}
+	return t.graph, nil
+}
+
+// reachedThroughOther returns whether the node with the sequence number is a descendant of the target of any of the edges.
+func reachedThroughOther(edges []dot.Edge, seq int, descendants map[int]map[int]bool) bool {
+	for _, each := range edges {
+		if descendants[each.To().Seq()][seq] {
+			return true
+		}
+	}
+	return false
+}
+
+// Condensation returns a copy of the graph in which the nodes of each strongly connected component
+// with more than one node are merged into the node with the smallest id of that component.
+// The merged node keeps its attributes, gets missing attributes of the other members
+// and its label lists the labels of all members, one per line.
+// Edges between members are removed ; of the parallel edges this creates, only the first is kept.
+// With KeepRemovedEdges, only these parallel edges are kept (invisible) because the members no longer exist.
+func Condensation(g *dot.Graph, options ...TransformOption) *dot.Graph {
+	t := newTransform(g, options)
+	condensed := map[int]bool{}
+	for _, component := range StronglyConnectedComponents(t.graph) {
+		if len(component) < 2 {
+			continue
+		}
+		labels := []string{}
+		for _, each := range component {
+			labels = append(labels, memberLabel(each))
+		}
+		merged := component[0]
+		for _, each := range component[1:] {
+			merged = t.graph.MergeNodes(merged, each)
+		}
+		merged.Label(strings.Join(labels, "\n"))
+		condensed[merged.Seq()] = true
+	}
+	nodes := newGraphNodes(t.graph)
+	for _, n := range nodes.sorted {
+		seen := map[int]bool{}
+		for _, each := range nodes.outEdges(n) {
+			to := each.To().Seq()
+			if (seen[to] || to == n.Seq()) && (condensed[n.Seq()] || condensed[to]) {
+				t.remove(each)
+			}
+			seen[to] = true
+		}
+	}
+	return t.graph
+}
+
+// memberLabel returns the label of the node, or its id if it has no label.
+func memberLabel(n dot.Node) string {
+	if label := n.Attribute("label"); label != nil {
+		return fmt.Sprintf("%v", label)
+	}
+	return n.ID()
+}
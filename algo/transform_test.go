@@ -0,0 +1,96 @@
+package algo
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/eristocrates/dot"
+)
+
+func TestTransitiveReduction(t *testing.T) {
+	g := dot.NewGraph(dot.Directed)
+	a, b, c, d := g.Node("a"), g.Node("b"), g.Node("c"), g.Node("d")
+	a.Edge(b)
+	b.Edge(c)
+	a.Edge(c)
+	c.Edge(d)
+	a.Edge(d)
+	c.Edge(d)
+	reduced, err := TransitiveReduction(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := edgePath(allEdges(reduced)), "a->b b->c c->d"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	// the original is not changed
+	if got, want := len(allEdges(g)), 6; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestTransitiveReductionKeepRemoved(t *testing.T) {
+	g := dot.NewGraph(dot.Directed)
+	a, b, c := g.Node("a"), g.Node("b"), g.Node("c")
+	a.Edge(b)
+	b.Edge(c)
+	a.Edge(c)
+	reduced, _ := TransitiveReduction(g, KeepRemovedEdges{})
+	ra, _ := reduced.FindNodeById("a")
+	rc, _ := reduced.FindNodeById("c")
+	edges := reduced.FindEdges(ra, rc)
+	if got, want := len(edges), 1; got != want {
+		t.Fatalf("got [%v] want [%v]", got, want)
+	}
+	if got, want := edges[0].Attribute("style"), "invis"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestTransitiveReductionCycle(t *testing.T) {
+	g := dot.NewGraph(dot.Directed)
+	a, b := g.Node("a"), g.Node("b")
+	a.Edge(b)
+	b.Edge(a)
+	_, err := TransitiveReduction(g)
+	var cerr *CycleError
+	if !errors.As(err, &cerr) {
+		t.Errorf("expected CycleError, got %v", err)
+	}
+}
+
+func TestCondensation(t *testing.T) {
+	g := dot.NewGraph(dot.Directed)
+	sub := g.Subgraph("sub")
+	a, b, c := g.Node("a"), sub.Node("b").Label("B"), g.Node("c").SetAttribute("color", "red")
+	d := g.Node("d")
+	a.Edge(b)
+	a.Edge(c)
+	b.Edge(c)
+	c.Edge(b)
+	c.Edge(d)
+	b.Edge(d)
+	condensed := Condensation(g)
+	if got, want := edgePath(allEdges(condensed)), "a->b b->d"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	merged, _ := condensed.FindNodeById("b")
+	if got, want := merged.Attribute("label"), "B\nc"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := merged.Attribute("color"), "red"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if HasCycle(condensed) {
+		t.Error("expected no cycles")
+	}
+}
+
+// allEdges returns the edges of the graph ordered by source id, then by creation.
+func allEdges(g *dot.Graph) []dot.Edge {
+	edges := []dot.Edge{}
+	for _, each := range newGraphNodes(g).sorted {
+		edges = append(edges, g.OutEdges(each)...)
+	}
+	return edges
+}
@@ -1,5 +1,7 @@
 package dot
 
+import "strings"
+
 // HTML renders the provided content as graphviz HTML. Use of this
 // type is only valid for some attributes, like the 'label' attribute.
 type HTML string
@@ -15,9 +17,27 @@ type HTML string
 // proper escaping of special characters.
 type Literal string
 
+// labelText returns the text of a string or quoted Literal value, as Graphviz reads it before interpreting
+// escape sequences such as \l ; false for other values such as HTML.
+func labelText(v interface{}) (string, bool) {
+	switch text := v.(type) {
+	case string:
+		return text, true
+	case Literal:
+		if len(text) >= 2 && strings.HasPrefix(string(text), `"`) && strings.HasSuffix(string(text), `"`) {
+			return strings.ReplaceAll(string(text[1:len(text)-1]), `\"`, `"`), true
+		}
+	}
+	return "", false
+}
+
 // AttributesMap holds attribute=value pairs.
 type AttributesMap struct {
 	attributes map[string]interface{}
+	// validation is set if the root graph has the StrictAttributesOption
+	validation *attributeValidation
+	// kind of element for validation: graph, subgraph, node or edge
+	kind string
 }
 
 // SetAttributes sets multiple values for attributes (unless empty) taking a label,value list
@@ -38,6 +58,9 @@ func (a AttributesMap) SetAttribute(label string, value interface{}) {
 	if len(label) == 0 || value == nil {
 		return
 	}
+	if a.validation != nil {
+		a.validation.check(a.kind, label, value, a.attributes)
+	}
 	if s, ok := value.(string); ok {
 		if len(s) > 0 {
 			a.attributes[label] = s
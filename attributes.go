@@ -0,0 +1,264 @@
+package dot
+
+//go:generate go run ./internal/attrgen
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// AttributeInfo describes a Graphviz attribute.
+type AttributeInfo struct {
+	Name string
+	// UsedBy has the kinds of elements that use the attribute:
+	// G (root graph), S (subgraph), C (cluster), N (node) and E (edge).
+	UsedBy string
+	// Type is the Graphviz value type, such as "double", "color" or "shape".
+	Type string
+}
+
+// LookupAttribute returns the description of a Graphviz attribute.
+func LookupAttribute(name string) (AttributeInfo, bool) {
+	info, ok := attributeCatalogue[name]
+	return info, ok
+}
+
+// AttributeNames returns the sorted names of all Graphviz attributes.
+func AttributeNames() []string {
+	names := make([]string, 0, len(attributeCatalogue))
+	for each := range attributeCatalogue {
+		names = append(names, each)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Values returns the valid values if the type of the attribute is an enumeration ; nil otherwise.
+func (a AttributeInfo) Values() []string {
+	return append([]string{}, attributeEnums[a.Type]...)
+}
+
+// StrictAttributesOption makes the graph check each attribute that is set on it, its subgraphs, nodes and edges,
+// including defaults. Unknown names, names that do not apply to the kind of element and invalid values are reported.
+// Only applicable to the root graph, before adding subgraphs, nodes and edges.
+type StrictAttributesOption struct {
+	// Panic makes setting an invalid attribute panic ; otherwise the errors are recorded, see Graph.AttributeErrors.
+	// An edge with a port that is not in the label of its node is reported when it is created ;
+	// with Panic, writing the graph also panics on such an edge, see Graph.PortErrors.
+	Panic bool
+	// Allow has the names of other (non Graphviz) attributes that can be set, such as "link" for Mermaid output.
+	Allow []string
+}
+
+func (o StrictAttributesOption) Apply(g *Graph) {
+	v := &attributeValidation{panics: o.Panic, allowed: map[string]bool{}}
+	for _, each := range o.Allow {
+		v.allowed[each] = true
+	}
+	g.AttributesMap.validation = v
+	g.AttributesMap.kind = "graph"
+}
+
+// AttributeError describes an invalid attribute set on an element of a graph with the StrictAttributesOption.
+type AttributeError struct {
+	// Kind is "graph", "subgraph", "node" or "edge".
+	Kind    string
+	Name    string
+	Value   interface{}
+	Message string
+}
+
+func (e *AttributeError) Error() string {
+	return fmt.Sprintf("%s attribute %s=%v: %s", e.Kind, e.Name, e.Value, e.Message)
+}
+
+// AttributeErrors returns the errors recorded by the StrictAttributesOption (without Panic) ; in order of occurrence.
+func (g *Graph) AttributeErrors() []*AttributeError {
+	v := g.Root().AttributesMap.validation
+	if v == nil {
+		return nil
+	}
+	return append([]*AttributeError{}, v.errors...)
+}
+
+type attributeValidation struct {
+	panics  bool
+	allowed map[string]bool
+	errors  []*AttributeError
+}
+
+// copy returns a validation with the same settings and no errors.
+func (v *attributeValidation) copy() *attributeValidation {
+	allowed := make(map[string]bool, len(v.allowed))
+	for k, each := range v.allowed {
+		allowed[k] = each
+	}
+	return &attributeValidation{panics: v.panics, allowed: allowed}
+}
+
+// setValidation sets the validation of the (sub)graph, its defaults, nodes, edges and subgraphs, recursively.
+func (g *Graph) setValidation(v *attributeValidation) {
+	g.AttributesMap.validation = v
+	for id, each := range g.nodes {
+		each.validation = v
+		g.nodes[id] = each
+	}
+	for _, edges := range g.edgesFrom {
+		for i := range edges {
+			edges[i].validation = v
+		}
+	}
+	for _, each := range g.subgraphs {
+		each.setValidation(v)
+	}
+}
+
+// check reports the attribute if it is invalid for the kind of element with the attributes.
+// Without a colorscheme among the attributes, color names and indices of any scheme are accepted
+// because the defaults of the element may set one.
+func (v *attributeValidation) check(kind, name string, value interface{}, attributes map[string]interface{}) {
+	if v.allowed[name] {
+		return
+	}
+	colors := Color.validateInSomeScheme
+	if scheme, ok := attributes["colorscheme"]; ok {
+		colors = func(c Color) error { return c.ValidateIn(fmt.Sprint(scheme)) }
+	}
+	err := attributeError(kind, name, value, colors)
+	if err == nil {
+		return
+	}
+	if v.panics {
+		panic(err.Error())
+	}
+	v.errors = append(v.errors, err.(*AttributeError))
+}
+
+// checkPorts reports a port of the edge that is not in the label of its node, as its tailport or headport attribute.
+func (v *attributeValidation) checkPorts(e Edge) {
+	v.checkPort(e.from, "tailport", Port(e.fromPort))
+	v.checkPort(e.to, "headport", Port(e.toPort))
+}
+
+func (v *attributeValidation) checkPort(n Node, name string, p Port) {
+	err := checkPort(n, p)
+	if err == nil {
+		return
+	}
+	if v.panics {
+		panic(err.Error())
+	}
+	v.errors = append(v.errors, &AttributeError{Kind: "edge", Name: name, Value: p, Message: err.Error()})
+}
+
+// ValidateAttribute returns an *AttributeError if the name is unknown, does not apply to the kind of element
+// ("graph", "subgraph", "node" or "edge") or the value is invalid ; nil otherwise.
+// HTML and Literal values are not checked.
+// Color names are resolved in the X11 scheme ; use ValidateAttributeIn for an element with a colorscheme.
+func ValidateAttribute(kind, name string, value interface{}) error {
+	return attributeError(kind, name, value, Color.Validate)
+}
+
+// ValidateAttributeIn is like ValidateAttribute for an element of which the colorscheme attribute,
+// set on the element or its defaults, is the scheme ; see Color.ValidateIn.
+func ValidateAttributeIn(kind, name string, value interface{}, colorscheme string) error {
+	return attributeError(kind, name, value, func(c Color) error { return c.ValidateIn(colorscheme) })
+}
+
+// attributeError returns an *AttributeError if the attribute is invalid, validating colors with the function ; nil otherwise.
+func attributeError(kind, name string, value interface{}, colors func(Color) error) error {
+	message := validateAttribute(kind, name, value, colors)
+	if len(message) == 0 {
+		return nil
+	}
+	return &AttributeError{Kind: kind, Name: name, Value: value, Message: message}
+}
+
+// validateAttribute returns why the attribute is invalid ; empty if it is valid.
+func validateAttribute(kind, name string, value interface{}, colors func(Color) error) string {
+	info, ok := attributeCatalogue[name]
+	if !ok {
+		return "unknown attribute"
+	}
+	kinds := map[string]string{"graph": "G", "subgraph": "SC", "node": "N", "edge": "E"}[kind]
+	if !strings.ContainsAny(info.UsedBy, kinds) {
+		return fmt.Sprintf("not used by %s", kind)
+	}
+	switch value.(type) {
+	case HTML, Literal:
+		return ""
+	}
+	text := fmt.Sprintf("%v", value)
+	switch info.Type {
+	case "double":
+		if _, err := strconv.ParseFloat(text, 64); err != nil {
+			return "expected a number"
+		}
+	case "int":
+		if _, err := strconv.Atoi(text); err != nil {
+			return "expected an integer"
+		}
+	case "bool":
+		if _, err := strconv.Atoi(text); err != nil && !containsString([]string{"true", "false", "yes", "no"}, strings.ToLower(text)) {
+			return "expected true, false, yes, no or an integer"
+		}
+	case "style":
+		for _, each := range strings.Split(text, ",") {
+			each = strings.TrimSpace(each)
+			// e.g. setlinewidth(2)
+			if !strings.Contains(each, "(") && !containsString(attributeEnums["style"], each) {
+				return fmt.Sprintf("unknown style %q", each)
+			}
+		}
+	case "color":
+		if err := colors(Color(text)); err != nil {
+			return strings.TrimPrefix(err.Error(), fmt.Sprintf("invalid color %q: ", text))
+		}
+	case "arrowType":
+		if !isArrowType(text) {
+			return "unknown arrow type"
+		}
+	default:
+		if values, ok := attributeEnums[info.Type]; ok && !containsString(values, text) {
+			return fmt.Sprintf("expected one of %s", strings.Join(values, ", "))
+		}
+	}
+	return ""
+}
+
+// isArrowType returns whether the text is one to four arrow shapes, each with optional modifiers o, l and r.
+func isArrowType(text string) bool {
+	if containsString(attributeEnums["arrowType"], text) {
+		return true
+	}
+	shapes := 0
+	for len(text) > 0 && shapes < 4 {
+		text = strings.TrimPrefix(text, "o")
+		if strings.HasPrefix(text, "l") || strings.HasPrefix(text, "r") {
+			text = text[1:]
+		}
+		found := false
+		for _, each := range []string{"normal", "inv", "dot", "none", "tee", "empty", "diamond", "crow", "box", "vee", "curve", "icurve"} {
+			if strings.HasPrefix(text, each) {
+				text = text[len(each):]
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+		shapes++
+	}
+	return len(text) == 0
+}
+
+func joinStyles(styles []Style) string {
+	list := make([]string, len(styles))
+	for i, each := range styles {
+		list[i] = string(each)
+	}
+	return strings.Join(list, ",")
+}
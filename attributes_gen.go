@@ -0,0 +1,631 @@
+// Code generated by internal/attrgen from internal/attrgen/attributes.txt; DO NOT EDIT.
+
+package dot
+
+// attributeCatalogue has all Graphviz attributes by name.
+var attributeCatalogue = map[string]AttributeInfo{
+	"_background":        {Name: "_background", UsedBy: "G", Type: "string"},
+	"area":               {Name: "area", UsedBy: "NC", Type: "double"},
+	"arrowhead":          {Name: "arrowhead", UsedBy: "E", Type: "arrowType"},
+	"arrowsize":          {Name: "arrowsize", UsedBy: "E", Type: "double"},
+	"arrowtail":          {Name: "arrowtail", UsedBy: "E", Type: "arrowType"},
+	"bb":                 {Name: "bb", UsedBy: "GC", Type: "rect"},
+	"beautify":           {Name: "beautify", UsedBy: "G", Type: "bool"},
+	"bgcolor":            {Name: "bgcolor", UsedBy: "GC", Type: "color"},
+	"center":             {Name: "center", UsedBy: "G", Type: "bool"},
+	"charset":            {Name: "charset", UsedBy: "G", Type: "string"},
+	"class":              {Name: "class", UsedBy: "GCNE", Type: "string"},
+	"cluster":            {Name: "cluster", UsedBy: "C", Type: "bool"},
+	"color":              {Name: "color", UsedBy: "NEC", Type: "color"},
+	"colorscheme":        {Name: "colorscheme", UsedBy: "NECG", Type: "string"},
+	"comment":            {Name: "comment", UsedBy: "NEG", Type: "string"},
+	"compound":           {Name: "compound", UsedBy: "G", Type: "bool"},
+	"concentrate":        {Name: "concentrate", UsedBy: "G", Type: "bool"},
+	"constraint":         {Name: "constraint", UsedBy: "E", Type: "bool"},
+	"Damping":            {Name: "Damping", UsedBy: "G", Type: "double"},
+	"decorate":           {Name: "decorate", UsedBy: "E", Type: "bool"},
+	"defaultdist":        {Name: "defaultdist", UsedBy: "G", Type: "double"},
+	"dim":                {Name: "dim", UsedBy: "G", Type: "int"},
+	"dimen":              {Name: "dimen", UsedBy: "G", Type: "int"},
+	"dir":                {Name: "dir", UsedBy: "E", Type: "dirType"},
+	"diredgeconstraints": {Name: "diredgeconstraints", UsedBy: "G", Type: "string"},
+	"distortion":         {Name: "distortion", UsedBy: "N", Type: "double"},
+	"dpi":                {Name: "dpi", UsedBy: "G", Type: "double"},
+	"edgehref":           {Name: "edgehref", UsedBy: "E", Type: "string"},
+	"edgetarget":         {Name: "edgetarget", UsedBy: "E", Type: "string"},
+	"edgetooltip":        {Name: "edgetooltip", UsedBy: "E", Type: "string"},
+	"edgeURL":            {Name: "edgeURL", UsedBy: "E", Type: "string"},
+	"epsilon":            {Name: "epsilon", UsedBy: "G", Type: "double"},
+	"esep":               {Name: "esep", UsedBy: "G", Type: "string"},
+	"fillcolor":          {Name: "fillcolor", UsedBy: "NEC", Type: "color"},
+	"fixedsize":          {Name: "fixedsize", UsedBy: "N", Type: "string"},
+	"fontcolor":          {Name: "fontcolor", UsedBy: "ENGC", Type: "color"},
+	"fontname":           {Name: "fontname", UsedBy: "ENGC", Type: "string"},
+	"fontnames":          {Name: "fontnames", UsedBy: "G", Type: "string"},
+	"fontpath":           {Name: "fontpath", UsedBy: "G", Type: "string"},
+	"fontsize":           {Name: "fontsize", UsedBy: "ENGC", Type: "double"},
+	"forcelabels":        {Name: "forcelabels", UsedBy: "G", Type: "bool"},
+	"gradientangle":      {Name: "gradientangle", UsedBy: "NCG", Type: "int"},
+	"group":              {Name: "group", UsedBy: "N", Type: "string"},
+	"head_lp":            {Name: "head_lp", UsedBy: "E", Type: "point"},
+	"headclip":           {Name: "headclip", UsedBy: "E", Type: "bool"},
+	"headhref":           {Name: "headhref", UsedBy: "E", Type: "string"},
+	"headlabel":          {Name: "headlabel", UsedBy: "E", Type: "lblString"},
+	"headport":           {Name: "headport", UsedBy: "E", Type: "portPos"},
+	"headtarget":         {Name: "headtarget", UsedBy: "E", Type: "string"},
+	"headtooltip":        {Name: "headtooltip", UsedBy: "E", Type: "string"},
+	"headURL":            {Name: "headURL", UsedBy: "E", Type: "string"},
+	"height":             {Name: "height", UsedBy: "N", Type: "double"},
+	"href":               {Name: "href", UsedBy: "GCNE", Type: "string"},
+	"id":                 {Name: "id", UsedBy: "GCNE", Type: "string"},
+	"image":              {Name: "image", UsedBy: "N", Type: "string"},
+	"imagepath":          {Name: "imagepath", UsedBy: "G", Type: "string"},
+	"imagepos":           {Name: "imagepos", UsedBy: "N", Type: "string"},
+	"imagescale":         {Name: "imagescale", UsedBy: "N", Type: "string"},
+	"inputscale":         {Name: "inputscale", UsedBy: "G", Type: "double"},
+	"K":                  {Name: "K", UsedBy: "GC", Type: "double"},
+	"label":              {Name: "label", UsedBy: "ENGC", Type: "lblString"},
+	"label_scheme":       {Name: "label_scheme", UsedBy: "G", Type: "int"},
+	"labelangle":         {Name: "labelangle", UsedBy: "E", Type: "double"},
+	"labeldistance":      {Name: "labeldistance", UsedBy: "E", Type: "double"},
+	"labelfloat":         {Name: "labelfloat", UsedBy: "E", Type: "bool"},
+	"labelfontcolor":     {Name: "labelfontcolor", UsedBy: "E", Type: "color"},
+	"labelfontname":      {Name: "labelfontname", UsedBy: "E", Type: "string"},
+	"labelfontsize":      {Name: "labelfontsize", UsedBy: "E", Type: "double"},
+	"labelhref":          {Name: "labelhref", UsedBy: "E", Type: "string"},
+	"labeljust":          {Name: "labeljust", UsedBy: "GC", Type: "string"},
+	"labelloc":           {Name: "labelloc", UsedBy: "NGC", Type: "string"},
+	"labeltarget":        {Name: "labeltarget", UsedBy: "E", Type: "string"},
+	"labeltooltip":       {Name: "labeltooltip", UsedBy: "E", Type: "string"},
+	"labelURL":           {Name: "labelURL", UsedBy: "E", Type: "string"},
+	"landscape":          {Name: "landscape", UsedBy: "G", Type: "bool"},
+	"layer":              {Name: "layer", UsedBy: "ENC", Type: "string"},
+	"layerlistsep":       {Name: "layerlistsep", UsedBy: "G", Type: "string"},
+	"layers":             {Name: "layers", UsedBy: "G", Type: "string"},
+	"layerselect":        {Name: "layerselect", UsedBy: "G", Type: "string"},
+	"layersep":           {Name: "layersep", UsedBy: "G", Type: "string"},
+	"layout":             {Name: "layout", UsedBy: "G", Type: "string"},
+	"len":                {Name: "len", UsedBy: "E", Type: "double"},
+	"levels":             {Name: "levels", UsedBy: "G", Type: "int"},
+	"levelsgap":          {Name: "levelsgap", UsedBy: "G", Type: "double"},
+	"lhead":              {Name: "lhead", UsedBy: "E", Type: "string"},
+	"lheight":            {Name: "lheight", UsedBy: "GC", Type: "double"},
+	"linelength":         {Name: "linelength", UsedBy: "G", Type: "int"},
+	"lp":                 {Name: "lp", UsedBy: "EGC", Type: "point"},
+	"ltail":              {Name: "ltail", UsedBy: "E", Type: "string"},
+	"lwidth":             {Name: "lwidth", UsedBy: "GC", Type: "double"},
+	"margin":             {Name: "margin", UsedBy: "NCG", Type: "string"},
+	"maxiter":            {Name: "maxiter", UsedBy: "G", Type: "int"},
+	"mclimit":            {Name: "mclimit", UsedBy: "G", Type: "double"},
+	"mindist":            {Name: "mindist", UsedBy: "G", Type: "double"},
+	"minlen":             {Name: "minlen", UsedBy: "E", Type: "int"},
+	"mode":               {Name: "mode", UsedBy: "G", Type: "string"},
+	"model":              {Name: "model", UsedBy: "G", Type: "string"},
+	"newrank":            {Name: "newrank", UsedBy: "G", Type: "bool"},
+	"nodesep":            {Name: "nodesep", UsedBy: "G", Type: "double"},
+	"nojustify":          {Name: "nojustify", UsedBy: "GCN", Type: "bool"},
+	"normalize":          {Name: "normalize", UsedBy: "G", Type: "string"},
+	"notranslate":        {Name: "notranslate", UsedBy: "G", Type: "bool"},
+	"nslimit":            {Name: "nslimit", UsedBy: "G", Type: "double"},
+	"nslimit1":           {Name: "nslimit1", UsedBy: "G", Type: "double"},
+	"oneblock":           {Name: "oneblock", UsedBy: "G", Type: "bool"},
+	"ordering":           {Name: "ordering", UsedBy: "GN", Type: "string"},
+	"orientation":        {Name: "orientation", UsedBy: "NG", Type: "string"},
+	"outputorder":        {Name: "outputorder", UsedBy: "G", Type: "outputMode"},
+	"overlap":            {Name: "overlap", UsedBy: "G", Type: "string"},
+	"overlap_scaling":    {Name: "overlap_scaling", UsedBy: "G", Type: "double"},
+	"overlap_shrink":     {Name: "overlap_shrink", UsedBy: "G", Type: "bool"},
+	"pack":               {Name: "pack", UsedBy: "G", Type: "string"},
+	"packmode":           {Name: "packmode", UsedBy: "G", Type: "string"},
+	"pad":                {Name: "pad", UsedBy: "G", Type: "string"},
+	"page":               {Name: "page", UsedBy: "G", Type: "string"},
+	"pagedir":            {Name: "pagedir", UsedBy: "G", Type: "pagedir"},
+	"pencolor":           {Name: "pencolor", UsedBy: "C", Type: "color"},
+	"penwidth":           {Name: "penwidth", UsedBy: "CNE", Type: "double"},
+	"peripheries":        {Name: "peripheries", UsedBy: "NC", Type: "int"},
+	"pin":                {Name: "pin", UsedBy: "N", Type: "bool"},
+	"pos":                {Name: "pos", UsedBy: "EN", Type: "string"},
+	"quadtree":           {Name: "quadtree", UsedBy: "G", Type: "string"},
+	"quantum":            {Name: "quantum", UsedBy: "G", Type: "double"},
+	"rank":               {Name: "rank", UsedBy: "S", Type: "rankType"},
+	"rankdir":            {Name: "rankdir", UsedBy: "G", Type: "rankdir"},
+	"ranksep":            {Name: "ranksep", UsedBy: "G", Type: "string"},
+	"ratio":              {Name: "ratio", UsedBy: "G", Type: "string"},
+	"rects":              {Name: "rects", UsedBy: "N", Type: "rect"},
+	"regular":            {Name: "regular", UsedBy: "N", Type: "bool"},
+	"remincross":         {Name: "remincross", UsedBy: "G", Type: "bool"},
+	"repulsiveforce":     {Name: "repulsiveforce", UsedBy: "G", Type: "double"},
+	"resolution":         {Name: "resolution", UsedBy: "G", Type: "double"},
+	"root":               {Name: "root", UsedBy: "GN", Type: "string"},
+	"rotate":             {Name: "rotate", UsedBy: "G", Type: "int"},
+	"rotation":           {Name: "rotation", UsedBy: "G", Type: "double"},
+	"samehead":           {Name: "samehead", UsedBy: "E", Type: "string"},
+	"sametail":           {Name: "sametail", UsedBy: "E", Type: "string"},
+	"samplepoints":       {Name: "samplepoints", UsedBy: "N", Type: "int"},
+	"scale":              {Name: "scale", UsedBy: "G", Type: "string"},
+	"searchsize":         {Name: "searchsize", UsedBy: "G", Type: "int"},
+	"sep":                {Name: "sep", UsedBy: "G", Type: "string"},
+	"shape":              {Name: "shape", UsedBy: "N", Type: "shape"},
+	"shapefile":          {Name: "shapefile", UsedBy: "N", Type: "string"},
+	"showboxes":          {Name: "showboxes", UsedBy: "ENG", Type: "int"},
+	"sides":              {Name: "sides", UsedBy: "N", Type: "int"},
+	"size":               {Name: "size", UsedBy: "G", Type: "string"},
+	"skew":               {Name: "skew", UsedBy: "N", Type: "double"},
+	"smoothing":          {Name: "smoothing", UsedBy: "G", Type: "string"},
+	"sortv":              {Name: "sortv", UsedBy: "GCN", Type: "int"},
+	"splines":            {Name: "splines", UsedBy: "G", Type: "splines"},
+	"start":              {Name: "start", UsedBy: "G", Type: "string"},
+	"style":              {Name: "style", UsedBy: "ENCG", Type: "style"},
+	"stylesheet":         {Name: "stylesheet", UsedBy: "G", Type: "string"},
+	"tail_lp":            {Name: "tail_lp", UsedBy: "E", Type: "point"},
+	"tailclip":           {Name: "tailclip", UsedBy: "E", Type: "bool"},
+	"tailhref":           {Name: "tailhref", UsedBy: "E", Type: "string"},
+	"taillabel":          {Name: "taillabel", UsedBy: "E", Type: "lblString"},
+	"tailport":           {Name: "tailport", UsedBy: "E", Type: "portPos"},
+	"tailtarget":         {Name: "tailtarget", UsedBy: "E", Type: "string"},
+	"tailtooltip":        {Name: "tailtooltip", UsedBy: "E", Type: "string"},
+	"tailURL":            {Name: "tailURL", UsedBy: "E", Type: "string"},
+	"target":             {Name: "target", UsedBy: "ENGC", Type: "string"},
+	"TBbalance":          {Name: "TBbalance", UsedBy: "G", Type: "string"},
+	"tooltip":            {Name: "tooltip", UsedBy: "NEC", Type: "string"},
+	"truecolor":          {Name: "truecolor", UsedBy: "G", Type: "bool"},
+	"URL":                {Name: "URL", UsedBy: "ENGC", Type: "string"},
+	"vertices":           {Name: "vertices", UsedBy: "N", Type: "string"},
+	"viewport":           {Name: "viewport", UsedBy: "G", Type: "string"},
+	"voro_margin":        {Name: "voro_margin", UsedBy: "G", Type: "double"},
+	"weight":             {Name: "weight", UsedBy: "E", Type: "double"},
+	"width":              {Name: "width", UsedBy: "N", Type: "double"},
+	"xdotversion":        {Name: "xdotversion", UsedBy: "G", Type: "string"},
+	"xlabel":             {Name: "xlabel", UsedBy: "EN", Type: "lblString"},
+	"xlp":                {Name: "xlp", UsedBy: "NE", Type: "point"},
+	"z":                  {Name: "z", UsedBy: "N", Type: "double"},
+}
+
+// attributeEnums has the valid values by value type.
+var attributeEnums = map[string][]string{
+	"shape":      {"box", "polygon", "ellipse", "oval", "circle", "point", "egg", "triangle", "plaintext", "plain", "diamond", "trapezium", "parallelogram", "house", "pentagon", "hexagon", "septagon", "octagon", "doublecircle", "doubleoctagon", "tripleoctagon", "invtriangle", "invtrapezium", "invhouse", "Mdiamond", "Msquare", "Mcircle", "rect", "rectangle", "square", "star", "none", "underline", "cylinder", "note", "tab", "folder", "box3d", "component", "promoter", "cds", "terminator", "utr", "primersite", "restrictionsite", "fivepoverhang", "threepoverhang", "noverhang", "assembly", "signature", "insulator", "ribosite", "rnastab", "proteasesite", "proteinstab", "rpromoter", "rarrow", "larrow", "lpromoter", "record", "Mrecord"},
+	"arrowType":  {"normal", "inv", "dot", "invdot", "odot", "invodot", "none", "tee", "empty", "invempty", "diamond", "odiamond", "ediamond", "crow", "box", "obox", "open", "halfopen", "vee"},
+	"dirType":    {"forward", "back", "both", "none"},
+	"rankType":   {"same", "min", "source", "max", "sink"},
+	"rankdir":    {"TB", "LR", "BT", "RL"},
+	"splines":    {"none", "line", "polyline", "curved", "ortho", "spline", "true", "false", "compound"},
+	"style":      {"solid", "dashed", "dotted", "bold", "invis", "filled", "striped", "wedged", "diagonals", "rounded", "radial", "tapered"},
+	"outputMode": {"breadthfirst", "nodesfirst", "edgesfirst"},
+	"pagedir":    {"BL", "BR", "TL", "TR", "RB", "RT", "LB", "LT"},
+}
+
+// Shape is a value of the Graphviz type shape.
+type Shape string
+
+const (
+	ShapeBox             Shape = "box"
+	ShapePolygon         Shape = "polygon"
+	ShapeEllipse         Shape = "ellipse"
+	ShapeOval            Shape = "oval"
+	ShapeCircle          Shape = "circle"
+	ShapePoint           Shape = "point"
+	ShapeEgg             Shape = "egg"
+	ShapeTriangle        Shape = "triangle"
+	ShapePlaintext       Shape = "plaintext"
+	ShapePlain           Shape = "plain"
+	ShapeDiamond         Shape = "diamond"
+	ShapeTrapezium       Shape = "trapezium"
+	ShapeParallelogram   Shape = "parallelogram"
+	ShapeHouse           Shape = "house"
+	ShapePentagon        Shape = "pentagon"
+	ShapeHexagon         Shape = "hexagon"
+	ShapeSeptagon        Shape = "septagon"
+	ShapeOctagon         Shape = "octagon"
+	ShapeDoublecircle    Shape = "doublecircle"
+	ShapeDoubleoctagon   Shape = "doubleoctagon"
+	ShapeTripleoctagon   Shape = "tripleoctagon"
+	ShapeInvtriangle     Shape = "invtriangle"
+	ShapeInvtrapezium    Shape = "invtrapezium"
+	ShapeInvhouse        Shape = "invhouse"
+	ShapeMdiamond        Shape = "Mdiamond"
+	ShapeMsquare         Shape = "Msquare"
+	ShapeMcircle         Shape = "Mcircle"
+	ShapeRect            Shape = "rect"
+	ShapeRectangle       Shape = "rectangle"
+	ShapeSquare          Shape = "square"
+	ShapeStar            Shape = "star"
+	ShapeNone            Shape = "none"
+	ShapeUnderline       Shape = "underline"
+	ShapeCylinder        Shape = "cylinder"
+	ShapeNote            Shape = "note"
+	ShapeTab             Shape = "tab"
+	ShapeFolder          Shape = "folder"
+	ShapeBox3d           Shape = "box3d"
+	ShapeComponent       Shape = "component"
+	ShapePromoter        Shape = "promoter"
+	ShapeCds             Shape = "cds"
+	ShapeTerminator      Shape = "terminator"
+	ShapeUtr             Shape = "utr"
+	ShapePrimersite      Shape = "primersite"
+	ShapeRestrictionsite Shape = "restrictionsite"
+	ShapeFivepoverhang   Shape = "fivepoverhang"
+	ShapeThreepoverhang  Shape = "threepoverhang"
+	ShapeNoverhang       Shape = "noverhang"
+	ShapeAssembly        Shape = "assembly"
+	ShapeSignature       Shape = "signature"
+	ShapeInsulator       Shape = "insulator"
+	ShapeRibosite        Shape = "ribosite"
+	ShapeRnastab         Shape = "rnastab"
+	ShapeProteasesite    Shape = "proteasesite"
+	ShapeProteinstab     Shape = "proteinstab"
+	ShapeRpromoter       Shape = "rpromoter"
+	ShapeRarrow          Shape = "rarrow"
+	ShapeLarrow          Shape = "larrow"
+	ShapeLpromoter       Shape = "lpromoter"
+	ShapeRecord          Shape = "record"
+	ShapeMrecord         Shape = "Mrecord"
+)
+
+// ArrowType is a value of the Graphviz type arrowType.
+type ArrowType string
+
+const (
+	ArrowNormal   ArrowType = "normal"
+	ArrowInv      ArrowType = "inv"
+	ArrowDot      ArrowType = "dot"
+	ArrowInvdot   ArrowType = "invdot"
+	ArrowOdot     ArrowType = "odot"
+	ArrowInvodot  ArrowType = "invodot"
+	ArrowNone     ArrowType = "none"
+	ArrowTee      ArrowType = "tee"
+	ArrowEmpty    ArrowType = "empty"
+	ArrowInvempty ArrowType = "invempty"
+	ArrowDiamond  ArrowType = "diamond"
+	ArrowOdiamond ArrowType = "odiamond"
+	ArrowEdiamond ArrowType = "ediamond"
+	ArrowCrow     ArrowType = "crow"
+	ArrowBox      ArrowType = "box"
+	ArrowObox     ArrowType = "obox"
+	ArrowOpen     ArrowType = "open"
+	ArrowHalfopen ArrowType = "halfopen"
+	ArrowVee      ArrowType = "vee"
+)
+
+// DirType is a value of the Graphviz type dirType.
+type DirType string
+
+const (
+	DirForward DirType = "forward"
+	DirBack    DirType = "back"
+	DirBoth    DirType = "both"
+	DirNone    DirType = "none"
+)
+
+// RankType is a value of the Graphviz type rankType.
+type RankType string
+
+const (
+	RankSame   RankType = "same"
+	RankMin    RankType = "min"
+	RankSource RankType = "source"
+	RankMax    RankType = "max"
+	RankSink   RankType = "sink"
+)
+
+// RankDir is a value of the Graphviz type rankdir.
+type RankDir string
+
+const (
+	TB RankDir = "TB"
+	LR RankDir = "LR"
+	BT RankDir = "BT"
+	RL RankDir = "RL"
+)
+
+// Splines is a value of the Graphviz type splines.
+type Splines string
+
+const (
+	SplinesNone     Splines = "none"
+	SplinesLine     Splines = "line"
+	SplinesPolyline Splines = "polyline"
+	SplinesCurved   Splines = "curved"
+	SplinesOrtho    Splines = "ortho"
+	SplinesSpline   Splines = "spline"
+	SplinesTrue     Splines = "true"
+	SplinesFalse    Splines = "false"
+	SplinesCompound Splines = "compound"
+)
+
+// Style is a value of the Graphviz type style.
+type Style string
+
+const (
+	StyleSolid     Style = "solid"
+	StyleDashed    Style = "dashed"
+	StyleDotted    Style = "dotted"
+	StyleBold      Style = "bold"
+	StyleInvis     Style = "invis"
+	StyleFilled    Style = "filled"
+	StyleStriped   Style = "striped"
+	StyleWedged    Style = "wedged"
+	StyleDiagonals Style = "diagonals"
+	StyleRounded   Style = "rounded"
+	StyleRadial    Style = "radial"
+	StyleTapered   Style = "tapered"
+)
+
+// OutputMode is a value of the Graphviz type outputMode.
+type OutputMode string
+
+const (
+	OutputOrderBreadthfirst OutputMode = "breadthfirst"
+	OutputOrderNodesfirst   OutputMode = "nodesfirst"
+	OutputOrderEdgesfirst   OutputMode = "edgesfirst"
+)
+
+// PageDir is a value of the Graphviz type pagedir.
+type PageDir string
+
+const (
+	PageDirBL PageDir = "BL"
+	PageDirBR PageDir = "BR"
+	PageDirTL PageDir = "TL"
+	PageDirTR PageDir = "TR"
+	PageDirRB PageDir = "RB"
+	PageDirRT PageDir = "RT"
+	PageDirLB PageDir = "LB"
+	PageDirLT PageDir = "LT"
+)
+
+// Color sets the "color" attribute.
+func (n Node) Color(v Color) Node {
+	return n.SetAttribute("color", string(v))
+}
+
+// FillColor sets the "fillcolor" attribute.
+func (n Node) FillColor(v Color) Node {
+	return n.SetAttribute("fillcolor", string(v))
+}
+
+// FontColor sets the "fontcolor" attribute.
+func (n Node) FontColor(v Color) Node {
+	return n.SetAttribute("fontcolor", string(v))
+}
+
+// FontName sets the "fontname" attribute.
+func (n Node) FontName(v string) Node {
+	return n.SetAttribute("fontname", v)
+}
+
+// FontSize sets the "fontsize" attribute.
+func (n Node) FontSize(v float64) Node {
+	return n.SetAttribute("fontsize", v)
+}
+
+// Height sets the "height" attribute.
+func (n Node) Height(v float64) Node {
+	return n.SetAttribute("height", v)
+}
+
+// PenWidth sets the "penwidth" attribute.
+func (n Node) PenWidth(v float64) Node {
+	return n.SetAttribute("penwidth", v)
+}
+
+// Shape sets the "shape" attribute.
+func (n Node) Shape(v Shape) Node {
+	return n.SetAttribute("shape", string(v))
+}
+
+// Style sets the "style" attribute.
+func (n Node) Style(styles ...Style) Node {
+	return n.SetAttribute("style", joinStyles(styles))
+}
+
+// Tooltip sets the "tooltip" attribute.
+func (n Node) Tooltip(v string) Node {
+	return n.SetAttribute("tooltip", v)
+}
+
+// URL sets the "URL" attribute.
+func (n Node) URL(v string) Node {
+	return n.SetAttribute("URL", v)
+}
+
+// Width sets the "width" attribute.
+func (n Node) Width(v float64) Node {
+	return n.SetAttribute("width", v)
+}
+
+// XLabel sets the "xlabel" attribute.
+func (n Node) XLabel(v string) Node {
+	return n.SetAttribute("xlabel", v)
+}
+
+// ArrowHead sets the "arrowhead" attribute.
+func (e Edge) ArrowHead(v ArrowType) Edge {
+	return e.SetAttribute("arrowhead", string(v))
+}
+
+// ArrowSize sets the "arrowsize" attribute.
+func (e Edge) ArrowSize(v float64) Edge {
+	return e.SetAttribute("arrowsize", v)
+}
+
+// ArrowTail sets the "arrowtail" attribute.
+func (e Edge) ArrowTail(v ArrowType) Edge {
+	return e.SetAttribute("arrowtail", string(v))
+}
+
+// Color sets the "color" attribute.
+func (e Edge) Color(v Color) Edge {
+	return e.SetAttribute("color", string(v))
+}
+
+// Constraint sets the "constraint" attribute.
+func (e Edge) Constraint(v bool) Edge {
+	return e.SetAttribute("constraint", v)
+}
+
+// Dir sets the "dir" attribute.
+func (e Edge) Dir(v DirType) Edge {
+	return e.SetAttribute("dir", string(v))
+}
+
+// FontColor sets the "fontcolor" attribute.
+func (e Edge) FontColor(v Color) Edge {
+	return e.SetAttribute("fontcolor", string(v))
+}
+
+// FontName sets the "fontname" attribute.
+func (e Edge) FontName(v string) Edge {
+	return e.SetAttribute("fontname", v)
+}
+
+// FontSize sets the "fontsize" attribute.
+func (e Edge) FontSize(v float64) Edge {
+	return e.SetAttribute("fontsize", v)
+}
+
+// LHead sets the "lhead" attribute.
+func (e Edge) LHead(v string) Edge {
+	return e.SetAttribute("lhead", v)
+}
+
+// LTail sets the "ltail" attribute.
+func (e Edge) LTail(v string) Edge {
+	return e.SetAttribute("ltail", v)
+}
+
+// MinLen sets the "minlen" attribute.
+func (e Edge) MinLen(v int) Edge {
+	return e.SetAttribute("minlen", v)
+}
+
+// PenWidth sets the "penwidth" attribute.
+func (e Edge) PenWidth(v float64) Edge {
+	return e.SetAttribute("penwidth", v)
+}
+
+// Style sets the "style" attribute.
+func (e Edge) Style(styles ...Style) Edge {
+	return e.SetAttribute("style", joinStyles(styles))
+}
+
+// Tooltip sets the "tooltip" attribute.
+func (e Edge) Tooltip(v string) Edge {
+	return e.SetAttribute("tooltip", v)
+}
+
+// URL sets the "URL" attribute.
+func (e Edge) URL(v string) Edge {
+	return e.SetAttribute("URL", v)
+}
+
+// Weight sets the "weight" attribute.
+func (e Edge) Weight(v float64) Edge {
+	return e.SetAttribute("weight", v)
+}
+
+// XLabel sets the "xlabel" attribute.
+func (e Edge) XLabel(v string) Edge {
+	return e.SetAttribute("xlabel", v)
+}
+
+// BgColor sets the "bgcolor" attribute.
+func (g *Graph) BgColor(v Color) *Graph {
+	g.SetAttribute("bgcolor", string(v))
+	return g
+}
+
+// Color sets the "color" attribute.
+func (g *Graph) Color(v Color) *Graph {
+	g.SetAttribute("color", string(v))
+	return g
+}
+
+// Compound sets the "compound" attribute.
+func (g *Graph) Compound(v bool) *Graph {
+	g.SetAttribute("compound", v)
+	return g
+}
+
+// Concentrate sets the "concentrate" attribute.
+func (g *Graph) Concentrate(v bool) *Graph {
+	g.SetAttribute("concentrate", v)
+	return g
+}
+
+// FillColor sets the "fillcolor" attribute.
+func (g *Graph) FillColor(v Color) *Graph {
+	g.SetAttribute("fillcolor", string(v))
+	return g
+}
+
+// FontColor sets the "fontcolor" attribute.
+func (g *Graph) FontColor(v Color) *Graph {
+	g.SetAttribute("fontcolor", string(v))
+	return g
+}
+
+// FontName sets the "fontname" attribute.
+func (g *Graph) FontName(v string) *Graph {
+	g.SetAttribute("fontname", v)
+	return g
+}
+
+// FontSize sets the "fontsize" attribute.
+func (g *Graph) FontSize(v float64) *Graph {
+	g.SetAttribute("fontsize", v)
+	return g
+}
+
+// NewRank sets the "newrank" attribute.
+func (g *Graph) NewRank(v bool) *Graph {
+	g.SetAttribute("newrank", v)
+	return g
+}
+
+// NodeSep sets the "nodesep" attribute.
+func (g *Graph) NodeSep(v float64) *Graph {
+	g.SetAttribute("nodesep", v)
+	return g
+}
+
+// PenWidth sets the "penwidth" attribute.
+func (g *Graph) PenWidth(v float64) *Graph {
+	g.SetAttribute("penwidth", v)
+	return g
+}
+
+// Rank sets the "rank" attribute.
+func (g *Graph) Rank(v RankType) *Graph {
+	g.SetAttribute("rank", string(v))
+	return g
+}
+
+// RankDir sets the "rankdir" attribute.
+func (g *Graph) RankDir(v RankDir) *Graph {
+	g.SetAttribute("rankdir", string(v))
+	return g
+}
+
+// Splines sets the "splines" attribute.
+func (g *Graph) Splines(v Splines) *Graph {
+	g.SetAttribute("splines", string(v))
+	return g
+}
+
+// Style sets the "style" attribute.
+func (g *Graph) Style(styles ...Style) *Graph {
+	g.SetAttribute("style", joinStyles(styles))
+	return g
+}
+
+// Tooltip sets the "tooltip" attribute.
+func (g *Graph) Tooltip(v string) *Graph {
+	g.SetAttribute("tooltip", v)
+	return g
+}
+
+// URL sets the "URL" attribute.
+func (g *Graph) URL(v string) *Graph {
+	g.SetAttribute("URL", v)
+	return g
+}
@@ -0,0 +1,128 @@
+package dot
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTypedSetters(t *testing.T) {
+	di := NewGraph(Directed)
+	di.RankDir(LR).Splines(SplinesOrtho).NodeSep(0.5)
+	a := di.Node("a").Shape(ShapeCylinder).Style(StyleFilled, StyleRounded).FontSize(10)
+	b := di.Node("b")
+	a.Edge(b).ArrowHead(ArrowVee).Dir(DirBoth).MinLen(2).Constraint(false)
+	if got, want := flatten(di.String()), `digraph  {nodesep="0.5";rankdir="LR";splines="ortho";n1[fontsize="10",label="a",shape="cylinder",style="filled,rounded"];n2[label="b"];n1->n2[arrowhead="vee",constraint="false",dir="both",minlen="2"];}`; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestLookupAttribute(t *testing.T) {
+	info, ok := LookupAttribute("rankdir")
+	if !ok {
+		t.Fatal("expected rankdir")
+	}
+	if got, want := info.UsedBy+" "+strings.Join(info.Values(), ","), "G TB,LR,BT,RL"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if _, ok := LookupAttribute("fillcolour"); ok {
+		t.Error("expected unknown attribute")
+	}
+}
+
+func TestStrictAttributes(t *testing.T) {
+	di := NewGraph(Directed, StrictAttributesOption{Allow: []string{"link"}})
+	sub := di.Subgraph("sub", ClusterOption{})
+	sub.SetAttribute("rank", "same")
+	n := sub.Node("a").SetAttribute("fillcolour", "red").SetAttribute("shape", "rectange")
+	n.SetAttribute("style", "filled,setlinewidth(2)").SetAttribute("width", "wide")
+	e := n.Edge(n).SetAttribute("arrowhead", "lteeoldiamond").SetAttribute("arrowtail", "nope")
+	e.SetAttribute("link", "-.->").SetAttribute("shape", "box")
+	di.NodeDefaults().SetAttribute("rankdir", "LR")
+	di.SetAttribute("splines", "wavy")
+	got := []string{}
+	for _, each := range di.AttributeErrors() {
+		got = append(got, each.Error())
+	}
+	want := []string{
+		"node attribute fillcolour=red: unknown attribute",
+		"node attribute shape=rectange: expected one of " + strings.Join(attributeEnums["shape"], ", "),
+		"node attribute width=wide: expected a number",
+		"edge attribute arrowtail=nope: unknown arrow type",
+		"edge attribute shape=box: not used by edge",
+		"node attribute rankdir=LR: not used by node",
+		"graph attribute splines=wavy: expected one of " + strings.Join(attributeEnums["splines"], ", "),
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestStrictAttributesColorScheme(t *testing.T) {
+	di := NewGraph(Directed, StrictAttributesOption{})
+	di.NodeDefaults().SetAttribute("colorscheme", "blues9")
+	di.Node("a").SetAttribute("fillcolor", "3").SetAttribute("color", "rde")
+	di.Node("b").SetAttribute("colorscheme", "set39").SetAttribute("fillcolor", "9").SetAttribute("color", "10")
+	got := []string{}
+	for _, each := range di.AttributeErrors() {
+		got = append(got, each.Error())
+	}
+	want := []string{
+		`node attribute color=rde: unknown color name "rde"`,
+		`node attribute color=10: expected a color index in [1,9] of scheme "set39"`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestStrictAttributesDeepCopy(t *testing.T) {
+	di := NewGraph(Directed, StrictAttributesOption{Allow: []string{"link"}})
+	a := di.Subgraph("sub").Node("a")
+	a.Edge(a)
+	di.Node("b").SetAttribute("colour", "red")
+	copied := di.DeepCopy()
+	ca, _ := copied.FindNodeById("a")
+	ca.SetAttribute("fillcolour", "red").SetAttribute("link", "x")
+	copied.FindEdges(ca, ca)[0].SetAttribute("arrowhead", "nope")
+	if got, want := len(di.AttributeErrors()), 1; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := len(copied.AttributeErrors()), 2; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestStrictAttributesPanic(t *testing.T) {
+	defer func() {
+		if r := recover(); r != "node attribute fillcolour=red: unknown attribute" {
+			t.Errorf("unexpected panic %v", r)
+		}
+	}()
+	di := NewGraph(Directed, StrictAttributesOption{Panic: true})
+	di.Node("a").SetAttribute("fillcolour", "red")
+}
+
+func TestValidateAttribute(t *testing.T) {
+	if err := ValidateAttribute("subgraph", "bgcolor", "red"); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	err := ValidateAttribute("edge", "penwidth", "thick")
+	if got, want := err.Error(), "edge attribute penwidth=thick: expected a number"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	err = ValidateAttribute("node", "fillcolor", "rde:blue")
+	if got, want := err.Error(), `node attribute fillcolor=rde:blue: unknown color name "rde"`; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if err := ValidateAttributeIn("node", "fillcolor", "3:green", "blues9"); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	err = ValidateAttributeIn("node", "fillcolor", "10", "blues9")
+	if got, want := err.Error(), `node attribute fillcolor=10: expected a color index in [1,9] of scheme "blues9"`; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	names := AttributeNames()
+	if got, want := names[0], "Damping"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
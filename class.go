@@ -0,0 +1,118 @@
+package dot
+
+import (
+	"sort"
+	"strings"
+)
+
+// DefineClass defines a named set of attributes for the nodes, edges and subgraphs that have that class ; see Node.Class.
+// A class defined in a subgraph is only used inside that subgraph and hides a class with the same name of its parents.
+// When writing, the attributes of the classes are combined with the attributes of an element:
+// attributes of later classes override those of earlier classes and the element's own attributes override both.
+// Defaults of the (sub)graphs still apply to attributes that are not set by classes or by the element itself.
+func (g *Graph) DefineClass(name string, attributes map[string]interface{}) *Graph {
+	copied := make(map[string]interface{}, len(attributes))
+	for k, v := range attributes {
+		copied[k] = v
+	}
+	g.classes[name] = copied
+	return g
+}
+
+// ClassAttributes returns a copy of the attributes of the class as defined in this (sub)graph or its nearest parent.
+func (g *Graph) ClassAttributes(name string) (map[string]interface{}, bool) {
+	definer, ok := g.classDefiner(name)
+	if !ok {
+		return nil, false
+	}
+	attributes := definer.classes[name]
+	copied := make(map[string]interface{}, len(attributes))
+	for k, v := range attributes {
+		copied[k] = v
+	}
+	return copied, true
+}
+
+// ClassNames returns the sorted names of the classes defined in this (sub)graph, without those of its parents.
+func (g *Graph) ClassNames() []string {
+	names := make([]string, 0, len(g.classes))
+	for each := range g.classes {
+		names = append(names, each)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// classDefiner returns this (sub)graph or its nearest parent that defines the class.
+func (g *Graph) classDefiner(name string) (*Graph, bool) {
+	for each := g; each != nil; each = each.parent {
+		if _, ok := each.classes[name]; ok {
+			return each, true
+		}
+	}
+	return nil, false
+}
+
+// Class adds the class names to the "class" attribute of the subgraph.
+func (g *Graph) Class(names ...string) *Graph {
+	addClasses(g.AttributesMap, names)
+	return g
+}
+
+// Class adds the class names to the "class" attribute of the node ; see Graph.DefineClass.
+func (n Node) Class(names ...string) Node {
+	addClasses(n.AttributesMap, names)
+	return n
+}
+
+// Class adds the class names to the "class" attribute of the edge ; see Graph.DefineClass.
+func (e Edge) Class(names ...string) Edge {
+	addClasses(e.AttributesMap, names)
+	return e
+}
+
+// addClasses adds the names that are missing to the space separated "class" attribute.
+func addClasses(a AttributesMap, names []string) {
+	classes := classNames(a.attributes)
+	for _, each := range names {
+		if !containsString(classes, each) {
+			classes = append(classes, each)
+		}
+	}
+	if len(classes) > 0 {
+		a.attributes["class"] = strings.Join(classes, " ")
+	}
+}
+
+// classNames returns the names in the "class" attribute.
+func classNames(attributes map[string]interface{}) []string {
+	if class, ok := attributes["class"].(string); ok {
+		return strings.Fields(class)
+	}
+	return []string{}
+}
+
+// resolvedAttributes returns the attributes combined with those of their classes, as visible from this (sub)graph.
+// Returns the attributes themselves if there are no defined classes to combine.
+func (g *Graph) resolvedAttributes(attributes map[string]interface{}) map[string]interface{} {
+	var resolved map[string]interface{}
+	for _, name := range classNames(attributes) {
+		class, ok := g.ClassAttributes(name)
+		if !ok {
+			continue
+		}
+		if resolved == nil {
+			resolved = map[string]interface{}{}
+		}
+		for k, v := range class {
+			resolved[k] = v
+		}
+	}
+	if resolved == nil {
+		return attributes
+	}
+	for k, v := range attributes {
+		resolved[k] = v
+	}
+	return resolved
+}
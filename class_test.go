@@ -0,0 +1,87 @@
+package dot
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestClassCascade(t *testing.T) {
+	di := NewGraph(Directed)
+	di.DefineClass("db", map[string]interface{}{"shape": "cylinder", "color": "blue"})
+	di.DefineClass("primary", map[string]interface{}{"color": "red", "penwidth": "2"})
+	di.Node("a").Class("db")
+	di.Node("b").Class("db", "primary")
+	di.Node("c").Class("db").SetAttribute("color", "green")
+	if got, want := flatten(di.String()), `digraph  {n1[class="db",color="blue",label="a",shape="cylinder"];n2[class="db primary",color="red",label="b",penwidth="2",shape="cylinder"];n3[class="db",color="green",label="c",shape="cylinder"];}`; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestClassInSubgraph(t *testing.T) {
+	di := NewGraph(Directed)
+	di.DefineClass("svc", map[string]interface{}{"shape": "box"})
+	sub := di.Subgraph("sub", ClusterOption{})
+	sub.DefineClass("svc", map[string]interface{}{"shape": "ellipse"})
+	sub.DefineClass("group", map[string]interface{}{"style": "filled"})
+	sub.Class("group")
+	a := di.Node("a").Class("svc")
+	b := sub.Node("b").Class("svc")
+	a.Edge(b).Class("svc")
+	if got, want := flatten(di.String()), `digraph  {subgraph cluster_s1 {class="group";label="sub";style="filled";n3[class="svc",label="b",shape="ellipse"];}n2[class="svc",label="a",shape="box"];n2->n3[class="svc",shape="box"];}`; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	// Class does not add a name twice
+	a.Class("svc")
+	if got, want := a.Attribute("class"), "svc"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if _, ok := sub.ClassAttributes("missing"); ok {
+		t.Error("expected missing class")
+	}
+}
+
+func TestClassEqualAndCopy(t *testing.T) {
+	di := NewGraph(Directed, NodeIDOption{})
+	di.DefineClass("db", map[string]interface{}{"shape": "cylinder"})
+	di.Node("a").Class("db")
+	parsed, err := ParseString(di.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !di.Equal(parsed) {
+		t.Error("expected equal after applying classes")
+	}
+	if got, want := di.DeepCopy().String(), di.String(); got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestClassMermaidShadowed(t *testing.T) {
+	di := NewGraph(Directed)
+	di.DefineClass("db", map[string]interface{}{"fillcolor": "lightblue"})
+	sub := di.Subgraph("sub")
+	sub.DefineClass("db", map[string]interface{}{"fillcolor": "pink"})
+	di.Node("a").Class("db")
+	sub.Node("b").Class("db")
+	got := MermaidFlowchart(di, MermaidTopDown)
+	for _, want := range []string{"classDef db fill:lightblue;", "class n2 db;", "classDef db_s1 fill:pink;", "class n3 db_s1;"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing [%v] in [%v]", want, got)
+		}
+	}
+}
+
+func TestClassMermaid(t *testing.T) {
+	di := NewGraph(Directed)
+	di.DefineClass("db", map[string]interface{}{"shape": "cylinder", "fillcolor": "lightblue"})
+	di.DefineClass("hot", map[string]interface{}{"color": "red"})
+	a := di.Node("a").Class("db")
+	b := di.Node("b").Class("db")
+	a.Edge(b).Class("hot")
+	got := MermaidFlowchart(di, MermaidTopDown)
+	for _, want := range []string{`n1[("a")];`, "classDef db fill:lightblue;", "class n1,n2 db;", "linkStyle 0 stroke:red;"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing [%v] in [%v]", want, got)
+		}
+	}
+}
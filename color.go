@@ -0,0 +1,460 @@
+package dot
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Color is a Graphviz color value. It is one of:
+//
+//	a name, such as "red" or "LightBlue", from the X11 scheme or else the SVG scheme
+//	"#RRGGBB" or "#RRGGBBAA"
+//	an HSV triple, such as "0.000 1.000 1.000" (each in [0,1])
+//	a scheme color, such as "/blues9/3", "/svg/green" or "//red" (the default X11 scheme)
+//	a list, such as "red:blue", for gradient or striped fills and parallel edge colors ;
+//	each color can have a weight, such as "red;0.3:blue", that is its fraction of the whole
+//
+// See https://graphviz.org/docs/attr-types/color/ and https://graphviz.org/docs/attr-types/colorList/.
+type Color string
+
+// Black and White are the results of Contrast.
+const (
+	Black Color = "black"
+	White Color = "white"
+)
+
+// RGB returns the color as "#RRGGBB".
+func RGB(r, g, b uint8) Color {
+	return Color(fmt.Sprintf("#%02x%02x%02x", r, g, b))
+}
+
+// RGBA returns the color as "#RRGGBBAA".
+func RGBA(r, g, b, a uint8) Color {
+	return Color(fmt.Sprintf("#%02x%02x%02x%02x", r, g, b, a))
+}
+
+// HSV returns the color for a hue, saturation and value, each in [0,1].
+func HSV(h, s, v float64) Color {
+	return Color(fmt.Sprintf("%.3f %.3f %.3f", h, s, v))
+}
+
+// SchemeColor returns the color with the name in a color scheme, such as "/svg/green".
+func SchemeColor(scheme, name string) Color {
+	return Color(fmt.Sprintf("/%s/%s", scheme, name))
+}
+
+// BrewerColor returns the color with the index (starting at 1) of a Brewer scheme, such as "/blues9/3".
+func BrewerColor(scheme string, index int) Color {
+	return Color(fmt.Sprintf("/%s/%d", scheme, index))
+}
+
+// ColorStop is a color of a list with its weight: the fraction of the whole ;
+// a zero weight means an equal share of what the other weights leave.
+type ColorStop struct {
+	Color  Color
+	Weight float64
+}
+
+// Gradient returns the list of colors, such as "red:blue" ; with style=filled it fills a node from the first to the last color.
+func Gradient(colors ...Color) Color {
+	stops := make([]ColorStop, len(colors))
+	for i, each := range colors {
+		stops[i] = ColorStop{Color: each}
+	}
+	return WeightedGradient(stops...)
+}
+
+// WeightedGradient returns the list of colors with their weights, such as "red;0.3:blue".
+func WeightedGradient(stops ...ColorStop) Color {
+	parts := make([]string, len(stops))
+	for i, each := range stops {
+		parts[i] = string(each.Color)
+		if each.Weight > 0 {
+			parts[i] += ";" + strconv.FormatFloat(each.Weight, 'f', -1, 64)
+		}
+	}
+	return Color(strings.Join(parts, ":"))
+}
+
+// ParseColor returns the Color if the text is a valid color or color list ; see Color.
+func ParseColor(text string) (Color, error) {
+	c := Color(strings.TrimSpace(text))
+	return c, c.Validate()
+}
+
+// String returns the color as written in dot.
+func (c Color) String() string { return string(c) }
+
+// Stops returns the colors of a list with their weights ; a single color has one stop.
+// The colors themselves are not validated.
+func (c Color) Stops() []ColorStop {
+	stops := []ColorStop{}
+	for _, each := range strings.Split(string(c), ":") {
+		stop := ColorStop{Color: Color(each)}
+		if name, weight, ok := strings.Cut(each, ";"); ok {
+			stop.Color = Color(name)
+			stop.Weight, _ = strconv.ParseFloat(weight, 64)
+		}
+		stops = append(stops, stop)
+	}
+	return stops
+}
+
+// Validate returns an error if the color, or a color of the list, is invalid or the weights exceed 1.
+// Color names are resolved in the X11 (and SVG) scheme ; use ValidateIn for an element with a colorscheme.
+func (c Color) Validate() error {
+	return c.validate(func(name string) error {
+		_, err := resolveColor(name)
+		return err
+	})
+}
+
+// ValidateIn is like Validate for an element of which the colorscheme attribute is the scheme:
+// color names and indices are resolved in that scheme first and then, as Graphviz does, in the X11 scheme.
+func (c Color) ValidateIn(scheme string) error {
+	return c.validate(func(name string) error {
+		_, err := resolveColorIn(scheme, name)
+		return err
+	})
+}
+
+// validateInSomeScheme is like Validate but accepts color names and indices that resolve in any color scheme,
+// for an element of which the colorscheme is not known.
+func (c Color) validateInSomeScheme() error {
+	return c.validate(func(name string) error {
+		_, err := resolveColor(name)
+		if err != nil && isSchemeColor(name) && resolvesInSomeScheme(name) {
+			return nil
+		}
+		return err
+	})
+}
+
+// validate returns an error if a color of the list does not resolve or the weights exceed 1.
+func (c Color) validate(resolve func(name string) error) error {
+	total := 0.0
+	for _, each := range strings.Split(string(c), ":") {
+		name, weight, weighted := strings.Cut(each, ";")
+		if weighted {
+			w, err := strconv.ParseFloat(weight, 64)
+			if err != nil || w < 0 || w > 1 {
+				return fmt.Errorf("invalid color %q: weight %q must be a number in [0,1]", c, weight)
+			}
+			total += w
+		}
+		if err := resolve(name); err != nil && !errors.As(err, new(unknownSchemeColorsError)) {
+			return fmt.Errorf("invalid color %q: %v", c, err)
+		}
+	}
+	if total > 1+1e-9 {
+		return fmt.Errorf("invalid color %q: weights add up to more than 1", c)
+	}
+	return nil
+}
+
+// ToRGBA returns the red, green, blue and alpha components of the color ; the first color of a list.
+// Returns an error if the color is invalid or it is in a Brewer scheme of which the colors are not known.
+func (c Color) ToRGBA() (color.RGBA, error) {
+	return resolveColor(string(c.Stops()[0].Color))
+}
+
+// Lighten returns the color with its lightness increased by the fraction (in [0,1]) of what is left to white,
+// as "#RRGGBB" (or "#RRGGBBAA") ; each color of a list is lightened. Returns the color unchanged if it cannot be resolved.
+func (c Color) Lighten(fraction float64) Color {
+	fraction = math.Max(0, math.Min(1, fraction))
+	return c.mapLightness(func(l float64) float64 { return l + (1-l)*fraction })
+}
+
+// Darken returns the color with its lightness decreased by the fraction (in [0,1]) of what is left to black,
+// as "#RRGGBB" (or "#RRGGBBAA") ; each color of a list is darkened. Returns the color unchanged if it cannot be resolved.
+func (c Color) Darken(fraction float64) Color {
+	fraction = math.Max(0, math.Min(1, fraction))
+	return c.mapLightness(func(l float64) float64 { return l * (1 - fraction) })
+}
+
+// Contrast returns Black or White, whichever is most readable on the color (the first color of a list),
+// such as for the fontcolor of a filled node. Returns Black if the color cannot be resolved.
+func (c Color) Contrast() Color {
+	rgba, err := c.ToRGBA()
+	if err != nil {
+		return Black
+	}
+	// WCAG contrast ratios against black (0) and white (1)
+	l := relativeLuminance(rgba)
+	if (l+0.05)/0.05 >= 1.05/(l+0.05) {
+		return Black
+	}
+	return White
+}
+
+func (c Color) mapLightness(f func(l float64) float64) Color {
+	stops := c.Stops()
+	for i, each := range stops {
+		rgba, err := resolveColor(string(each.Color))
+		if err != nil {
+			return c
+		}
+		h, s, l := toHSL(rgba)
+		r, g, b := fromHSL(h, s, f(l))
+		stops[i].Color = RGB(r, g, b)
+		if rgba.A != 0xff {
+			stops[i].Color = RGBA(r, g, b, rgba.A)
+		}
+	}
+	return WeightedGradient(stops...)
+}
+
+// resolveColor returns the components of a single color.
+func resolveColor(text string) (color.RGBA, error) {
+	text = strings.TrimSpace(text)
+	switch {
+	case len(text) == 0:
+		return color.RGBA{}, fmt.Errorf("empty color")
+	case strings.HasPrefix(text, "#"):
+		return resolveHexColor(text)
+	case strings.HasPrefix(text, "/"):
+		scheme, name, ok := strings.Cut(text[1:], "/")
+		if !ok {
+			return color.RGBA{}, fmt.Errorf("expected /scheme/color")
+		}
+		return resolveSchemeColor(scheme, name)
+	case strings.ContainsAny(text[:1], "0123456789."):
+		return resolveHSVColor(text)
+	}
+	return resolveSchemeColor("", text)
+}
+
+// resolveColorIn returns the components of a single color of an element with the colorscheme ;
+// a name or index is resolved in the scheme first and then in the X11 scheme.
+func resolveColorIn(scheme, text string) (color.RGBA, error) {
+	text = strings.TrimSpace(text)
+	if len(scheme) > 0 && isSchemeColor(text) {
+		rgba, err := resolveSchemeColor(scheme, text)
+		if err == nil || errors.As(err, new(unknownSchemeColorsError)) {
+			return rgba, err
+		}
+		if fallback, fallbackErr := resolveColor(text); fallbackErr == nil {
+			return fallback, nil
+		}
+		return rgba, err
+	}
+	return resolveColor(text)
+}
+
+// isSchemeColor returns whether the color is a name or index to resolve in the colorscheme,
+// rather than a "#RRGGBB", "/scheme/name" or "H,S,V" color.
+func isSchemeColor(text string) bool {
+	text = strings.TrimSpace(text)
+	return len(text) > 0 && !strings.ContainsAny(text[:1], "#/") && !strings.ContainsAny(text, ", ")
+}
+
+// resolvesInSomeScheme returns whether the color name or index is valid in at least one color scheme.
+func resolvesInSomeScheme(text string) bool {
+	text = strings.ToLower(strings.TrimSpace(text))
+	for _, named := range namedColors {
+		if _, ok := named[text]; ok {
+			return true
+		}
+	}
+	index, err := strconv.Atoi(text)
+	if err != nil {
+		return false
+	}
+	for _, family := range brewerFamilies {
+		if index >= 1 && index <= family.maxClasses {
+			return true
+		}
+	}
+	return false
+}
+
+func resolveHexColor(text string) (color.RGBA, error) {
+	digits := text[1:]
+	if len(digits) != 6 && len(digits) != 8 {
+		return color.RGBA{}, fmt.Errorf("expected #RRGGBB or #RRGGBBAA")
+	}
+	if len(digits) == 6 {
+		digits += "ff"
+	}
+	v, err := strconv.ParseUint(digits, 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("expected hexadecimal digits")
+	}
+	return color.RGBA{R: uint8(v >> 24), G: uint8(v >> 16), B: uint8(v >> 8), A: uint8(v)}, nil
+}
+
+func resolveHSVColor(text string) (color.RGBA, error) {
+	fields := strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == ' ' })
+	if len(fields) != 3 {
+		return color.RGBA{}, fmt.Errorf("expected H,S,V")
+	}
+	hsv := [3]float64{}
+	for i, each := range fields {
+		v, err := strconv.ParseFloat(each, 64)
+		if err != nil || v < 0 || v > 1 {
+			return color.RGBA{}, fmt.Errorf("expected H,S,V with numbers in [0,1]")
+		}
+		hsv[i] = v
+	}
+	r, g, b := fromHSV(hsv[0], hsv[1], hsv[2])
+	return color.RGBA{R: r, G: g, B: b, A: 0xff}, nil
+}
+
+// resolveSchemeColor returns the color with the name in the scheme ; the default scheme is X11, falling back to SVG.
+func resolveSchemeColor(scheme, name string) (color.RGBA, error) {
+	scheme, name = strings.ToLower(scheme), strings.ToLower(name)
+	if name == "transparent" {
+		return color.RGBA{R: 0xff, G: 0xff, B: 0xfe, A: 0}, nil
+	}
+	schemes := []string{scheme}
+	if len(scheme) == 0 {
+		schemes = []string{"x11", "svg"}
+	}
+	for _, each := range schemes {
+		if named, ok := namedColors[each]; ok {
+			if v, ok := named[name]; ok {
+				return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}, nil
+			}
+		}
+	}
+	if _, ok := namedColors[scheme]; ok || len(scheme) == 0 {
+		return color.RGBA{}, fmt.Errorf("unknown color name %q", name)
+	}
+	family, classes, ok := lookupBrewerScheme(scheme)
+	if !ok {
+		return color.RGBA{}, fmt.Errorf("unknown color scheme %q", scheme)
+	}
+	index, err := strconv.Atoi(name)
+	if err != nil || index < 1 || index > classes {
+		return color.RGBA{}, fmt.Errorf("expected a color index in [1,%d] of scheme %q", classes, scheme)
+	}
+	if len(family.colors) == 0 {
+		return color.RGBA{}, unknownSchemeColorsError{scheme: scheme}
+	}
+	v := family.colors[index-1]
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}, nil
+}
+
+// brewerFamily describes the Brewer schemes named by the family and number of classes, such as blues3 to blues9.
+type brewerFamily struct {
+	minClasses, maxClasses int
+	// colors of the scheme with the most classes ; nil if not known.
+	// The schemes with fewer classes of these (qualitative) families use the first colors.
+	colors []uint32
+}
+
+// unknownSchemeColorsError is returned for a valid color of a Brewer scheme of which the colors are not known.
+type unknownSchemeColorsError struct {
+	scheme string
+}
+
+func (e unknownSchemeColorsError) Error() string {
+	return fmt.Sprintf("colors of scheme %q are not known", e.scheme)
+}
+
+// lookupBrewerScheme returns the family and number of classes of a scheme name such as "set312".
+func lookupBrewerScheme(scheme string) (brewerFamily, int, bool) {
+	for i := len(scheme) - 1; i > 0; i-- {
+		family, ok := brewerFamilies[scheme[:i]]
+		if !ok {
+			continue
+		}
+		classes, err := strconv.Atoi(scheme[i:])
+		if err == nil && classes >= family.minClasses && classes <= family.maxClasses {
+			return family, classes, true
+		}
+	}
+	return brewerFamily{}, 0, false
+}
+
+func relativeLuminance(c color.RGBA) float64 {
+	linear := func(v uint8) float64 {
+		s := float64(v) / 255
+		if s <= 0.03928 {
+			return s / 12.92
+		}
+		return math.Pow((s+0.055)/1.055, 2.4)
+	}
+	return 0.2126*linear(c.R) + 0.7152*linear(c.G) + 0.0722*linear(c.B)
+}
+
+func toHSL(c color.RGBA) (h, s, l float64) {
+	r, g, b := float64(c.R)/255, float64(c.G)/255, float64(c.B)/255
+	high, low := math.Max(r, math.Max(g, b)), math.Min(r, math.Min(g, b))
+	l = (high + low) / 2
+	if high == low {
+		return 0, 0, l
+	}
+	d := high - low
+	if l > 0.5 {
+		s = d / (2 - high - low)
+	} else {
+		s = d / (high + low)
+	}
+	switch high {
+	case r:
+		h = (g - b) / d
+		if g < b {
+			h += 6
+		}
+	case g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+	return h / 6, s, l
+}
+
+func fromHSL(h, s, l float64) (r, g, b uint8) {
+	if s == 0 {
+		v := toByte(l)
+		return v, v, v
+	}
+	var q float64
+	if l < 0.5 {
+		q = l * (1 + s)
+	} else {
+		q = l + s - l*s
+	}
+	p := 2*l - q
+	hue := func(t float64) float64 {
+		t -= math.Floor(t)
+		switch {
+		case t < 1.0/6:
+			return p + (q-p)*6*t
+		case t < 0.5:
+			return q
+		case t < 2.0/3:
+			return p + (q-p)*(2.0/3-t)*6
+		}
+		return p
+	}
+	return toByte(hue(h + 1.0/3)), toByte(hue(h)), toByte(hue(h - 1.0/3))
+}
+
+func fromHSV(h, s, v float64) (r, g, b uint8) {
+	i := math.Floor(h * 6)
+	f := h*6 - i
+	p, q, t := v*(1-s), v*(1-f*s), v*(1-(1-f)*s)
+	switch int(i) % 6 {
+	case 0:
+		return toByte(v), toByte(t), toByte(p)
+	case 1:
+		return toByte(q), toByte(v), toByte(p)
+	case 2:
+		return toByte(p), toByte(v), toByte(t)
+	case 3:
+		return toByte(p), toByte(q), toByte(v)
+	case 4:
+		return toByte(t), toByte(p), toByte(v)
+	}
+	return toByte(v), toByte(p), toByte(q)
+}
+
+func toByte(fraction float64) uint8 {
+	return uint8(math.Round(math.Max(0, math.Min(1, fraction)) * 255))
+}
@@ -0,0 +1,97 @@
+package dot
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestColorValidate(t *testing.T) {
+	for _, each := range []Color{
+		"red", "LightBlue", "crimson", "transparent", "#ff000080", "#1E90FF", "0.000 1.000 1.000", "0.5,0.5,0.5",
+		"/blues9/3", "/set312/12", "/svg/green", "//red", "red:blue", "red;0.3:blue", "red;0.3:green;0.3:blue",
+		RGB(1, 2, 3), HSV(0.1, 0.2, 0.3), BrewerColor("dark28", 8), SchemeColor("x11", "gray"),
+	} {
+		if err := each.Validate(); err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+	}
+	for bad, want := range map[Color]string{
+		"rde":              `invalid color "rde": unknown color name "rde"`,
+		"#ff00":            `invalid color "#ff00": expected #RRGGBB or #RRGGBBAA`,
+		"#gg0000":          `invalid color "#gg0000": expected hexadecimal digits`,
+		"1.5 0 0":          `invalid color "1.5 0 0": expected H,S,V with numbers in [0,1]`,
+		"/blues10/1":       `invalid color "/blues10/1": unknown color scheme "blues10"`,
+		"/blues9/10":       `invalid color "/blues9/10": expected a color index in [1,9] of scheme "blues9"`,
+		"red;0.7:blue;0.7": `invalid color "red;0.7:blue;0.7": weights add up to more than 1`,
+		"red;x":            `invalid color "red;x": weight "x" must be a number in [0,1]`,
+		"red:":             `invalid color "red:": empty color`,
+	} {
+		err := bad.Validate()
+		if err == nil {
+			t.Errorf("expected error for %q", bad)
+			continue
+		}
+		if got := err.Error(); got != want {
+			t.Errorf("got [%v] want [%v]", got, want)
+		}
+	}
+}
+
+func TestColorToRGBA(t *testing.T) {
+	for c, want := range map[Color]color.RGBA{
+		"red":          {R: 255, A: 255},
+		"Gray":         {R: 190, G: 190, B: 190, A: 255},
+		"/svg/gray":    {R: 128, G: 128, B: 128, A: 255},
+		"teal":         {G: 128, B: 128, A: 255},
+		"#10203040":    {R: 16, G: 32, B: 48, A: 64},
+		"0.5 1 1":      {G: 255, B: 255, A: 255},
+		"/set19/2":     {R: 0x37, G: 0x7e, B: 0xb8, A: 255},
+		"blue;0.2:red": {B: 255, A: 255},
+	} {
+		got, err := c.ToRGBA()
+		if err != nil || got != want {
+			t.Errorf("%s: got [%v,%v] want [%v]", c, got, err, want)
+		}
+	}
+	if _, err := Color("/blues9/3").ToRGBA(); err == nil || err.Error() != `colors of scheme "blues9" are not known` {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestColorLightenDarkenContrast(t *testing.T) {
+	if got, want := Color("red").Lighten(0.5), Color("#ff8080"); got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := Color("#ff000080").Darken(0.5), Color("#80000080"); got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := Color("red;0.3:white").Darken(1), Color("#000000;0.3:#000000"); got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := Color("rde").Lighten(0.5), Color("rde"); got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := Color("navy").Contrast(), White; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := Color("yellow").Contrast(), Black; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestGradient(t *testing.T) {
+	if got, want := Gradient("red", "blue"), Color("red:blue"); got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	w := WeightedGradient(ColorStop{Color: "red", Weight: 0.25}, ColorStop{Color: "blue"})
+	if got, want := w, Color("red;0.25:blue"); got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := len(w.Stops()), 2; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	n := NewGraph(Directed).Node("a").FillColor(w).FontColor(Color("red").Contrast())
+	if got, want := flatten(n.Graph().String()), `digraph  {n1[fillcolor="red;0.25:blue",fontcolor="black",label="a"];}`; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
@@ -0,0 +1,119 @@
+package dot
+
+import "fmt"
+
+// NodeDefaults returns the default attributes for the nodes of this (sub)graph and its subgraphs.
+// Changes to the returned map are written as a node [...] statement at the top of the (sub)graph.
+// Attributes of a node (or its classes) override the defaults ; defaults of a subgraph override those of its parents.
+func (g *Graph) NodeDefaults() AttributesMap {
+	return AttributesMap{attributes: g.nodeDefaults, validation: g.AttributesMap.validation, kind: "node"}
+}
+
+// EdgeDefaults returns the default attributes for the edges of this (sub)graph and its subgraphs.
+// Changes to the returned map are written as an edge [...] statement at the top of the (sub)graph.
+// Attributes of an edge (or its classes) override the defaults ; defaults of a subgraph override those of its parents.
+func (g *Graph) EdgeDefaults() AttributesMap {
+	return AttributesMap{attributes: g.edgeDefaults, validation: g.AttributesMap.validation, kind: "edge"}
+}
+
+// GraphDefaults returns the default attributes for this (sub)graph and its subgraphs.
+// Changes to the returned map are written as a graph [...] statement at the top of the (sub)graph.
+// Attributes set on a (sub)graph override the defaults ; defaults of a subgraph override those of its parents.
+func (g *Graph) GraphDefaults() AttributesMap {
+	return AttributesMap{attributes: g.graphDefaults, validation: g.AttributesMap.validation, kind: "subgraph"}
+}
+
+// EffectiveAttributes returns a copy of the attributes of the node as Graphviz sees them:
+// the defaults of its (sub)graph and parents, overridden by those of its classes and then by its own.
+func (n Node) EffectiveAttributes() map[string]interface{} {
+	return n.Graph().effectiveAttributes(n.attributes, nodeDefaultsOf)
+}
+
+// EffectiveAttributes returns a copy of the attributes of the edge as Graphviz sees them:
+// the defaults of its (sub)graph and parents, overridden by those of its classes and then by its own.
+func (e Edge) EffectiveAttributes() map[string]interface{} {
+	return e.graph.effectiveAttributes(e.attributes, edgeDefaultsOf)
+}
+
+// HoistDefaultsOption makes the graph write attributes that all nodes (or all edges) of a (sub)graph have in common
+// as a single node [...] (or edge [...]) statement instead of repeating them for each element.
+// Only applicable to the root graph.
+type HoistDefaultsOption struct{}
+
+func (o HoistDefaultsOption) Apply(g *Graph) {
+	g.hoistDefaults = true
+}
+
+// inheritedDefaults returns the defaults of this (sub)graph combined with those of its parents.
+func (g *Graph) inheritedDefaults(defaultsOf func(*Graph) map[string]interface{}) map[string]interface{} {
+	combined := map[string]interface{}{}
+	if g.parent != nil {
+		combined = g.parent.inheritedDefaults(defaultsOf)
+	}
+	for k, v := range defaultsOf(g) {
+		combined[k] = v
+	}
+	return combined
+}
+
+func nodeDefaultsOf(g *Graph) map[string]interface{} { return g.nodeDefaults }
+
+func edgeDefaultsOf(g *Graph) map[string]interface{} { return g.edgeDefaults }
+
+func graphDefaultsOf(g *Graph) map[string]interface{} { return g.graphDefaults }
+
+// effectiveAttributes returns the attributes of an element of this (sub)graph as Graphviz sees them:
+// the inherited defaults, overridden by the attributes of its classes and then by its own attributes.
+func (g *Graph) effectiveAttributes(attributes map[string]interface{}, defaultsOf func(*Graph) map[string]interface{}) map[string]interface{} {
+	combined := g.inheritedDefaults(defaultsOf)
+	for k, v := range g.resolvedAttributes(attributes) {
+		combined[k] = v
+	}
+	return combined
+}
+
+// writeDefaults writes a node, edge or graph default statement, if there are attributes.
+func writeDefaults(w *IndentWriter, kind string, attributes map[string]interface{}) {
+	if len(attributes) == 0 {
+		return
+	}
+	fmt.Fprint(w, kind)
+	appendSortedMap(attributes, true, w)
+	fmt.Fprint(w, ";")
+	w.NewLine()
+}
+
+// sharedAttributes returns the attributes with the same value in all lists ; empty if there are fewer than two lists.
+func sharedAttributes(lists []map[string]interface{}) map[string]interface{} {
+	shared := map[string]interface{}{}
+	if len(lists) < 2 {
+		return shared
+	}
+	for k, v := range lists[0] {
+		same := true
+		for _, other := range lists[1:] {
+			if w, ok := other[k]; !ok || attributeValue(w) != attributeValue(v) {
+				same = false
+				break
+			}
+		}
+		if same {
+			shared[k] = v
+		}
+	}
+	return shared
+}
+
+// withoutAttributes returns the attributes that are not in the other map.
+func withoutAttributes(attributes, other map[string]interface{}) map[string]interface{} {
+	if len(other) == 0 {
+		return attributes
+	}
+	kept := map[string]interface{}{}
+	for k, v := range attributes {
+		if _, ok := other[k]; !ok {
+			kept[k] = v
+		}
+	}
+	return kept
+}
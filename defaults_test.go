@@ -0,0 +1,134 @@
+package dot
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestNodeAndEdgeDefaults(t *testing.T) {
+	di := NewGraph(Directed)
+	di.NodeDefaults().SetAttribute("fontname", "Helvetica")
+	di.EdgeDefaults().SetAttribute("color", "gray")
+	sub := di.Subgraph("sub")
+	sub.NodeDefaults().SetAttribute("shape", "box")
+	di.Node("a").Edge(sub.Node("b"))
+	if got, want := flatten(di.String()), `digraph  {node[fontname="Helvetica"];edge[color="gray"];subgraph s1 {node[shape="box"];label="sub";n3[label="b"];}n2[label="a"];n2->n3;}`; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestGraphDefaults(t *testing.T) {
+	di := NewGraph(Directed, NodeIDOption{})
+	di.GraphDefaults().SetAttribute("fontname", "Helvetica")
+	di.SetAttribute("rankdir", "LR")
+	sub := di.Subgraph("sub")
+	sub.Node("a")
+	if got, want := flatten(di.String()), `digraph  {graph[fontname="Helvetica"];subgraph s1 {label="sub";a[label="a"];}rankdir="LR";}`; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	parsed, err := ParseString(`digraph { graph [fontname="Helvetica"] subgraph s1 { label="sub" a } rankdir="LR" }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !di.Equal(parsed) {
+		t.Errorf("expected equal: %s", parsed.String())
+	}
+	if got, want := flatten(parsed.String()), `digraph  {subgraph s1 {label="sub";a[label="a"];}fontname="Helvetica";rankdir="LR";}`; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestDefaultsEqualParsed(t *testing.T) {
+	di := NewGraph(Directed, NodeIDOption{})
+	di.NodeDefaults().SetAttribute("shape", "box")
+	di.Node("a").Edge(di.Node("b"))
+	parsed, err := ParseString(di.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !di.Equal(parsed) {
+		t.Errorf("expected equal: %s", parsed.String())
+	}
+	other := NewGraph(Directed, NodeIDOption{})
+	other.Node("a").Edge(other.Node("b"))
+	if di.Equal(other) {
+		t.Error("expected not equal without defaults")
+	}
+	if got, want := di.DeepCopy().String(), di.String(); got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestHoistDefaults(t *testing.T) {
+	di := NewGraph(Directed, HoistDefaultsOption{})
+	sub := di.Subgraph("sub")
+	sub.Node("c")
+	a := di.Node("a").Box().SetAttribute("color", "red")
+	b := di.Node("b").Box().SetAttribute("color", "blue")
+	a.Edge(b).Bold()
+	b.Edge(a).Bold()
+	if got, want := flatten(di.String()), `digraph  {subgraph s1 {label="sub";n2[label="c"];}node[shape="box"];n3[color="red",label="a"];n4[color="blue",label="b"];edge[style="bold"];n3->n4;n4->n3;}`; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	// the written graph has the same attributes
+	parsed, err := ParseString(di.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, _ := parsed.FindNodeById("n3")
+	if got, want := n.Attribute("shape"), "box"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	c, _ := parsed.FindNodeById("n2")
+	if got, want := c.Attribute("shape"), interface{}(nil); got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestDefaultsMermaid(t *testing.T) {
+	di := NewGraph(Directed)
+	di.NodeDefaults().SetAttribute("shape", "cylinder")
+	di.Node("a")
+	if got, want := MermaidFlowchart(di, MermaidTopDown), `n1[("a")];`; !strings.Contains(got, want) {
+		t.Errorf("missing [%v] in [%v]", want, got)
+	}
+}
+
+func TestDefaultsMermaidWithClass(t *testing.T) {
+	di := NewGraph(Directed)
+	di.NodeDefaults().SetAttribute("fillcolor", "yellow")
+	di.DefineClass("db", map[string]interface{}{"fillcolor": "lightblue"})
+	di.Node("a").Class("db")
+	di.Node("b")
+	got := MermaidFlowchart(di, MermaidTopDown)
+	for _, want := range []string{"classDef db fill:lightblue;", "class n1 db;", "style n2 fill:yellow;"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing [%v] in [%v]", want, got)
+		}
+	}
+	if strings.Contains(got, "style n1") {
+		t.Errorf("defaults override the class in [%v]", got)
+	}
+}
+
+func TestEffectiveAttributes(t *testing.T) {
+	g := NewGraph(Directed)
+	g.NodeDefaults().SetAttribute("shape", "box")
+	g.EdgeDefaults().SetAttribute("color", "gray")
+	g.DefineClass("db", map[string]interface{}{"shape": "cylinder", "fillcolor": "blue"})
+	sub := g.Subgraph("sub")
+	sub.NodeDefaults().SetAttribute("fillcolor", "red")
+	a := sub.Node("a").Class("db")
+	e := a.Edge(g.Node("b")).SetAttribute("color", "red")
+	attributes := a.EffectiveAttributes()
+	if got, want := fmt.Sprintf("%v %v", attributes["shape"], attributes["fillcolor"]), "cylinder blue"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := e.EffectiveAttributes()["color"], "red"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := g.Node("b").EffectiveAttributes()["shape"], "box"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
@@ -0,0 +1,220 @@
+// Package dotdiff compares two versions of a dot.Graph.
+//
+// Nodes are matched by their id and edges by their nodes, ports and order of creation.
+// The Changes can be written as a textual changelog or rendered as a merged graph.
+//
+//	import "github.com/eristocrates/dot/dotdiff"
+package dotdiff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/eristocrates/dot"
+)
+
+// Changes has the differences between an old and a new graph.
+type Changes struct {
+	// AddedNodes has the ids of nodes that are only in the new graph, sorted.
+	AddedNodes []string
+	// RemovedNodes has the ids of nodes that are only in the old graph, sorted.
+	RemovedNodes []string
+	// ChangedNodes has the nodes in both graphs with different attributes or subgraph, sorted by id.
+	ChangedNodes []NodeChange
+	// AddedEdges has the edges that are only in the new graph.
+	AddedEdges []EdgeKey
+	// RemovedEdges has the edges that are only in the old graph.
+	RemovedEdges []EdgeKey
+	// ChangedEdges has the edges in both graphs with different attributes.
+	ChangedEdges []EdgeChange
+
+	old, new *dot.Graph
+}
+
+// NodeChange describes how a node with the same id differs.
+type NodeChange struct {
+	ID         string
+	Attributes []AttributeChange
+	// OldSubgraph and NewSubgraph are the subgraph paths (see SubgraphPath) ; equal if the node did not move.
+	OldSubgraph, NewSubgraph string
+}
+
+// Moved returns whether the node is in another subgraph.
+func (c NodeChange) Moved() bool {
+	return c.OldSubgraph != c.NewSubgraph
+}
+
+// EdgeKey identifies an edge by its nodes, ports and its position among the edges with the same nodes and ports.
+// For undirected graphs, From is the smallest id of both nodes.
+type EdgeKey struct {
+	From, FromPort string
+	To, ToPort     string
+	// Index is 0 for the first edge with these nodes and ports, 1 for the second, and so on.
+	Index int
+}
+
+// EdgeChange describes how an edge with the same key differs.
+type EdgeChange struct {
+	Edge       EdgeKey
+	Attributes []AttributeChange
+}
+
+// AttributeChange describes the old and new value of an attribute ; nil if absent.
+type AttributeChange struct {
+	Name     string
+	Old, New interface{}
+}
+
+// IsEmpty returns whether both graphs have the same nodes and edges.
+func (d Changes) IsEmpty() bool {
+	return len(d.AddedNodes)+len(d.RemovedNodes)+len(d.ChangedNodes)+
+		len(d.AddedEdges)+len(d.RemovedEdges)+len(d.ChangedEdges) == 0
+}
+
+// Diff returns the differences between the old and the new graph.
+func Diff(old, new *dot.Graph) Changes {
+	d := Changes{old: old, new: new}
+	oldNodes, newNodes := nodesByID(old), nodesByID(new)
+	for _, id := range sortedKeys(oldNodes) {
+		if _, ok := newNodes[id]; !ok {
+			d.RemovedNodes = append(d.RemovedNodes, id)
+		}
+	}
+	for _, id := range sortedKeys(newNodes) {
+		n := newNodes[id]
+		o, ok := oldNodes[id]
+		if !ok {
+			d.AddedNodes = append(d.AddedNodes, id)
+			continue
+		}
+		change := NodeChange{
+			ID:          id,
+			Attributes:  compareAttributes(o.Attributes(), n.Attributes()),
+			OldSubgraph: SubgraphPath(o.Graph()),
+			NewSubgraph: SubgraphPath(n.Graph()),
+		}
+		if len(change.Attributes) > 0 || change.Moved() {
+			d.ChangedNodes = append(d.ChangedNodes, change)
+		}
+	}
+	oldEdges, newEdges := edgesByKey(old), edgesByKey(new)
+	for _, key := range sortedEdgeKeys(oldEdges) {
+		if _, ok := newEdges[key]; !ok {
+			d.RemovedEdges = append(d.RemovedEdges, key)
+		}
+	}
+	for _, key := range sortedEdgeKeys(newEdges) {
+		o, ok := oldEdges[key]
+		if !ok {
+			d.AddedEdges = append(d.AddedEdges, key)
+			continue
+		}
+		if changes := compareAttributes(o.Attributes(), newEdges[key].Attributes()); len(changes) > 0 {
+			d.ChangedEdges = append(d.ChangedEdges, EdgeChange{Edge: key, Attributes: changes})
+		}
+	}
+	return d
+}
+
+// SubgraphPath returns the keys of the subgraph and its parents, separated by "/" ; empty for the root.
+func SubgraphPath(g *dot.Graph) string {
+	keys := []string{}
+	for each := g; each.Parent() != nil; each = each.Parent() {
+		keys = append([]string{each.Key()}, keys...)
+	}
+	return strings.Join(keys, "/")
+}
+
+func nodesByID(g *dot.Graph) map[string]dot.Node {
+	nodes := map[string]dot.Node{}
+	for _, each := range g.FindNodes() {
+		nodes[each.ID()] = each
+	}
+	return nodes
+}
+
+func sortedKeys(m map[string]dot.Node) []string {
+	keys := make([]string, 0, len(m))
+	for each := range m {
+		keys = append(keys, each)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// edgesByKey returns all edges of the graph by their key.
+func edgesByKey(g *dot.Graph) map[EdgeKey]dot.Edge {
+	edges := map[EdgeKey]dot.Edge{}
+	nodes := nodesByID(g)
+	for _, id := range sortedKeys(nodes) {
+		for _, each := range g.OutEdges(nodes[id]) {
+			key := EdgeKey{From: each.From().ID(), FromPort: each.FromPort(), To: each.To().ID(), ToPort: each.ToPort()}
+			if !g.IsDirected() && key.To < key.From {
+				key = EdgeKey{From: key.To, FromPort: key.ToPort, To: key.From, ToPort: key.FromPort}
+			}
+			for {
+				if _, taken := edges[key]; !taken {
+					break
+				}
+				key.Index++
+			}
+			edges[key] = each
+		}
+	}
+	return edges
+}
+
+func sortedEdgeKeys(m map[EdgeKey]dot.Edge) []EdgeKey {
+	keys := make([]EdgeKey, 0, len(m))
+	for each := range m {
+		keys = append(keys, each)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		if a.To != b.To {
+			return a.To < b.To
+		}
+		if a.FromPort != b.FromPort {
+			return a.FromPort < b.FromPort
+		}
+		if a.ToPort != b.ToPort {
+			return a.ToPort < b.ToPort
+		}
+		return a.Index < b.Index
+	})
+	return keys
+}
+
+// compareAttributes returns the changes sorted by name.
+func compareAttributes(old, new map[string]interface{}) []AttributeChange {
+	names := map[string]bool{}
+	for each := range old {
+		names[each] = true
+	}
+	for each := range new {
+		names[each] = true
+	}
+	changes := []AttributeChange{}
+	for name := range names {
+		o, n := old[name], new[name]
+		if !sameValue(o, n) {
+			changes = append(changes, AttributeChange{Name: name, Old: o, New: n})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+	return changes
+}
+
+// sameValue returns whether both values are the same, as text ; HTML labels differ from other values with the same text.
+func sameValue(one, two interface{}) bool {
+	if one == nil || two == nil {
+		return one == nil && two == nil
+	}
+	_, htmlOne := one.(dot.HTML)
+	_, htmlTwo := two.(dot.HTML)
+	return htmlOne == htmlTwo && fmt.Sprint(one) == fmt.Sprint(two)
+}
@@ -0,0 +1,64 @@
+package dotdiff
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/eristocrates/dot"
+)
+
+func parse(t *testing.T, src string) *dot.Graph {
+	t.Helper()
+	g, err := dot.ParseString(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestDiff(t *testing.T) {
+	old := parse(t, `digraph { a [color=red]; b; subgraph cluster_x { c }; a -> b; a -> c; a -> c }`)
+	new := parse(t, `digraph { a [color=blue]; d; subgraph cluster_x { }; c; a -> c [label=x]; a -> d }`)
+	d := Diff(old, new)
+	if got, want := fmt.Sprint(d.AddedNodes, d.RemovedNodes), "[d] [b]"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := len(d.ChangedNodes), 2; got != want {
+		t.Fatalf("got [%v] want [%v]", got, want)
+	}
+	if got, want := d.ChangedNodes[0].Attributes[0], (AttributeChange{Name: "color", Old: "red", New: "blue"}); got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := d.ChangedNodes[1].OldSubgraph+" "+d.ChangedNodes[1].NewSubgraph, "cluster_x "; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := fmt.Sprint(d.AddedEdges), "[{a  d  0}]"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := fmt.Sprint(d.RemovedEdges), "[{a  b  0} {a  c  1}]"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := d.ChangedEdges[0].Edge, (EdgeKey{From: "a", To: "c"}); got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestDiffEqual(t *testing.T) {
+	src := `graph { a -- b [label=<<b>x</b>>]; b:p -- c }`
+	if d := Diff(parse(t, src), parse(t, src)); !d.IsEmpty() {
+		t.Errorf("expected no changes, got %v", d.Changelog())
+	}
+	// undirected edges match in both directions, an HTML label differs from a string label
+	d := Diff(parse(t, src), parse(t, `graph { b -- a [label="<b>x</b>"]; c -- b:p }`))
+	if got, want := d.Changelog(), "~ edge a -- b: label \"<b>x</b>\" -> \"<b>x</b>\"\n"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestSubgraphPath(t *testing.T) {
+	g := dot.NewGraph(dot.Directed)
+	inner := g.Subgraph("outer", dot.ClusterOption{}).Subgraph("inner")
+	if got, want := SubgraphPath(inner), "outer/inner"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
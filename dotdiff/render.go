@@ -0,0 +1,149 @@
+package dotdiff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/eristocrates/dot"
+)
+
+const (
+	addedColor   = "green"
+	removedColor = "red"
+	changedColor = "orange"
+)
+
+// Render returns a copy of the new graph that also has the removed nodes and edges of the old graph.
+// Added nodes and edges are green, changed ones orange and removed ones red and dashed.
+// Removed nodes are put in their old subgraph, which is created if it no longer exists.
+func (d Changes) Render() *dot.Graph {
+	merged := d.new.DeepCopy()
+	mergedNodes, mergedEdges := nodesByID(merged), edgesByKey(merged)
+	for _, id := range d.AddedNodes {
+		markNode(mergedNodes[id], addedColor)
+	}
+	for _, each := range d.ChangedNodes {
+		markNode(mergedNodes[each.ID], changedColor)
+	}
+	oldNodes := nodesByID(d.old)
+	for _, id := range d.RemovedNodes {
+		old := oldNodes[id]
+		n := scopeFor(merged, old.Graph()).Node(id)
+		for k, v := range old.Attributes() {
+			n.SetAttribute(k, v)
+		}
+		markNode(n, removedColor).SetAttribute("style", "dashed")
+	}
+	for _, key := range d.AddedEdges {
+		markEdge(mergedEdges[key], addedColor)
+	}
+	for _, each := range d.ChangedEdges {
+		markEdge(mergedEdges[each.Edge], changedColor)
+	}
+	oldEdges := edgesByKey(d.old)
+	for _, key := range d.RemovedEdges {
+		old := oldEdges[key]
+		from, _ := merged.FindNodeById(old.From().ID())
+		to, _ := merged.FindNodeById(old.To().ID())
+		e := merged.EdgeWithPorts(from, to, old.FromPort(), old.ToPort())
+		for k, v := range old.Attributes() {
+			e.SetAttribute(k, v)
+		}
+		markEdge(e, removedColor).Dashed()
+	}
+	return merged
+}
+
+// scopeFor returns the (sub)graph of the merged graph with the same path as the old (sub)graph.
+// Missing subgraphs are created with the attributes of the old ones.
+func scopeFor(merged *dot.Graph, old *dot.Graph) *dot.Graph {
+	if old.Parent() == nil {
+		return merged
+	}
+	parent := scopeFor(merged, old.Parent())
+	if sub, ok := parent.FindSubgraph(old.Key()); ok && sub.Parent() == parent {
+		return sub
+	}
+	options := []dot.GraphOption{}
+	if strings.HasPrefix(old.ID(), "cluster") {
+		options = append(options, dot.ClusterOption{})
+	}
+	sub := parent.Subgraph(old.Key(), options...)
+	for k, v := range old.Attributes() {
+		sub.SetAttribute(k, v)
+	}
+	sub.SetAttribute("color", removedColor)
+	sub.SetAttribute("style", "dashed")
+	return sub
+}
+
+func markNode(n dot.Node, color string) dot.Node {
+	return n.SetAttribute("color", color).SetAttribute("fontcolor", color)
+}
+
+func markEdge(e dot.Edge, color string) dot.Edge {
+	return e.SetAttribute("color", color).SetAttribute("fontcolor", color)
+}
+
+// Changelog returns one line per change: "+" for added, "-" for removed and "~" for changed nodes and edges.
+func (d Changes) Changelog() string {
+	b := new(strings.Builder)
+	for _, id := range d.AddedNodes {
+		fmt.Fprintf(b, "+ node %s\n", id)
+	}
+	for _, id := range d.RemovedNodes {
+		fmt.Fprintf(b, "- node %s\n", id)
+	}
+	for _, each := range d.ChangedNodes {
+		details := attributeDetails(each.Attributes)
+		if each.Moved() {
+			details = append(details, fmt.Sprintf("subgraph %q -> %q", each.OldSubgraph, each.NewSubgraph))
+		}
+		fmt.Fprintf(b, "~ node %s: %s\n", each.ID, strings.Join(details, ", "))
+	}
+	for _, each := range d.AddedEdges {
+		fmt.Fprintf(b, "+ edge %s\n", d.edgeText(each))
+	}
+	for _, each := range d.RemovedEdges {
+		fmt.Fprintf(b, "- edge %s\n", d.edgeText(each))
+	}
+	for _, each := range d.ChangedEdges {
+		fmt.Fprintf(b, "~ edge %s: %s\n", d.edgeText(each.Edge), strings.Join(attributeDetails(each.Attributes), ", "))
+	}
+	return b.String()
+}
+
+// edgeText returns the edge as in DOT, followed by its index if it is not the first edge with these nodes and ports.
+func (d Changes) edgeText(key EdgeKey) string {
+	op := "->"
+	if !d.new.IsDirected() {
+		op = "--"
+	}
+	s := fmt.Sprintf("%s %s %s", withPort(key.From, key.FromPort), op, withPort(key.To, key.ToPort))
+	if key.Index > 0 {
+		s += fmt.Sprintf(" #%d", key.Index+1)
+	}
+	return s
+}
+
+func withPort(id, port string) string {
+	if len(port) == 0 {
+		return id
+	}
+	return id + ":" + port
+}
+
+func attributeDetails(changes []AttributeChange) []string {
+	details := []string{}
+	for _, each := range changes {
+		details = append(details, fmt.Sprintf("%s %s -> %s", each.Name, valueText(each.Old), valueText(each.New)))
+	}
+	return details
+}
+
+func valueText(v interface{}) string {
+	if v == nil {
+		return "(none)"
+	}
+	return fmt.Sprintf("%q", fmt.Sprint(v))
+}
@@ -0,0 +1,35 @@
+package dotdiff
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	old := parse(t, `digraph { subgraph cluster_x { label=X; b } a -> b [label=ab]; a -> c }`)
+	new := parse(t, `digraph { a -> c [color=black]; a -> d }`)
+	merged := Diff(old, new).Render()
+	if got, want := flatten(merged.String()), `digraph  {subgraph cluster_s4 {color="red";label="X";style="dashed";b[color="red",fontcolor="red",label="b",style="dashed"];}a[label="a"];c[label="c"];d[color="green",fontcolor="green",label="d"];a->c[color="orange",fontcolor="orange"];a->d[color="green",fontcolor="green"];a->b[color="red",fontcolor="red",label="ab",style="dashed"];}`; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestChangelog(t *testing.T) {
+	old := parse(t, `digraph { subgraph x { b } a -> b; a -> c }`)
+	new := parse(t, `digraph { b; a -> c [color=black]; a -> d; a:p -> d; a:p -> d }`)
+	want := `+ node d
+~ node b: subgraph "x" -> ""
++ edge a -> d
++ edge a:p -> d
++ edge a:p -> d #2
+- edge a -> b
+~ edge a -> c: color (none) -> "black"
+`
+	if got := Diff(old, new).Changelog(); got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func flatten(s string) string {
+	return strings.Replace((strings.Replace(s, "\n", "", -1)), "\t", "", -1)
+}
@@ -119,10 +119,10 @@ func (s *Composite) connect(portName string, isInput bool, inner dot.Node) dot.E
 	// node creation is idempotent
 	port := s.Node(portName).SetAttribute("shape", "point")
 	if isInput {
-		return s.EdgeWithPorts(port, inner, "s", "n").SetAttribute("taillabel", portName)
+		return s.PortEdge(port, dot.Port(dot.S), inner, dot.Port(dot.N)).SetAttribute("taillabel", portName)
 	} else {
 		// is output
-		return s.EdgeWithPorts(inner, port, "s", "n").SetAttribute("headlabel", portName)
+		return s.PortEdge(inner, dot.Port(dot.S), port, dot.Port(dot.N)).SetAttribute("headlabel", portName)
 	}
 }
 
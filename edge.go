@@ -6,6 +6,7 @@ type Edge struct {
 	graph            *Graph
 	from, to         Node
 	fromPort, toPort string
+	seq              int
 }
 
 // SetAttribute sets key=value and returns the Edge.
@@ -72,6 +73,16 @@ func (e Edge) To() Node {
 	return e.to
 }
 
+// FromPort returns the port of the From node ; empty if the edge has no port there.
+func (e Edge) FromPort() string {
+	return e.fromPort
+}
+
+// ToPort returns the port of the To node ; empty if the edge has no port there.
+func (e Edge) ToPort() string {
+	return e.toPort
+}
+
 // Returns a copy of the attributes for this edge.
 func (e Edge) Attributes() map[string]interface{} {
 	return e.AttributesMap.Attributes()
@@ -97,6 +97,18 @@ func TestEdgeWithSecondPort(t *testing.T) {
 	}
 }
 
+func TestEdgeFromPortAndToPort(t *testing.T) {
+	di := NewGraph(Directed)
+	a, b := di.Node("A"), di.Node("B")
+	e := di.EdgeWithPorts(a, b, "port_a", "port_b:s")
+	if got, want := e.FromPort()+" "+e.ToPort(), "port_a port_b:s"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := a.Edge(b).FromPort()+a.Edge(b).ToPort(), ""; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
 func TestEdgeSetLabel(t *testing.T) {
 	di := NewGraph(Directed)
 	n1 := di.Node("A")
@@ -0,0 +1,102 @@
+package dot
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Equal returns whether both graphs describe the same DOT graph.
+// The comparison is semantic: generated sequence numbers, subgraph lookup keys and the
+// subgraph in which an edge is stored are ignored. Compared are the graph type and identifier,
+// the subgraph tree (by identifier), the nodes (by identifier) of each (sub)graph, all edges
+// including ports, the rank groups and all attribute values as they are written in DOT.
+// Node and edge attributes are compared with their classes and the node and edge defaults applied.
+// Graph attributes are compared with the graph defaults of the same (sub)graph applied.
+func (g *Graph) Equal(other *Graph) bool {
+	if other == nil {
+		return false
+	}
+	return g.canonical() == other.canonical()
+}
+
+// canonical returns a deterministic description of the graph for comparison.
+func (g *Graph) canonical() string {
+	b := new(strings.Builder)
+	strict := ""
+	if g.isStrict {
+		strict = "strict "
+	}
+	fmt.Fprintf(b, "%s%s %q\n", strict, g.graphType, g.id)
+	g.canonicalScope(b)
+	edges := []string{}
+	g.visitScopes(func(each *Graph) {
+		for _, all := range each.edgesFrom {
+			for _, e := range all {
+				edges = append(edges, fmt.Sprintf("%q:%q -> %q:%q [%s]", e.from.id, e.fromPort, e.to.id, e.toPort, canonicalAttributes(e.graph.effectiveAttributes(e.attributes, edgeDefaultsOf))))
+			}
+		}
+	})
+	sort.Strings(edges)
+	for _, each := range edges {
+		fmt.Fprintln(b, each)
+	}
+	return b.String()
+}
+
+// canonicalScope writes the attributes, nodes, rank groups and subgraphs of a (sub)graph.
+func (g *Graph) canonicalScope(b *strings.Builder) {
+	// graph defaults are written as a graph statement of their own scope ; that reads back as attributes
+	graphAttributes := copyAttributes(g.graphDefaults)
+	for k, v := range g.resolvedAttributes(g.attributes) {
+		graphAttributes[k] = v
+	}
+	fmt.Fprintf(b, "graph [%s]\n", canonicalAttributes(graphAttributes))
+	for _, key := range g.sortedNodesKeys() {
+		fmt.Fprintf(b, "node %q [%s]\n", key, canonicalAttributes(g.effectiveAttributes(g.nodes[key].attributes, nodeDefaultsOf)))
+	}
+	for _, group := range []struct {
+		rank  string
+		ranks map[string][]Node
+	}{
+		{"same", g.sameRanks}, {"min", g.minRanks}, {"source", g.sourceRanks}, {"max", g.maxRanks}, {"sink", g.sinkRanks},
+	} {
+		for _, nodes := range group.ranks {
+			ids := []string{}
+			for _, n := range nodes {
+				ids = append(ids, fmt.Sprintf("%q", n.id))
+			}
+			sort.Strings(ids)
+			fmt.Fprintf(b, "rank=%s %s\n", group.rank, strings.Join(ids, " "))
+		}
+	}
+	subs := []string{}
+	for _, each := range g.subgraphs {
+		sb := new(strings.Builder)
+		fmt.Fprintf(sb, "subgraph %q {\n", each.id)
+		each.canonicalScope(sb)
+		fmt.Fprintln(sb, "}")
+		subs = append(subs, sb.String())
+	}
+	sort.Strings(subs)
+	for _, each := range subs {
+		b.WriteString(each)
+	}
+}
+
+func canonicalAttributes(m map[string]interface{}) string {
+	pairs := []string{}
+	for k, v := range m {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", k, attributeValue(v)))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// visitScopes calls the callback for the graph and all its subgraphs, recursively.
+func (g *Graph) visitScopes(callback func(each *Graph)) {
+	callback(g)
+	for _, each := range g.subgraphs {
+		each.visitScopes(callback)
+	}
+}
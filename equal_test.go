@@ -0,0 +1,93 @@
+package dot
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNodeIDOption(t *testing.T) {
+	g := NewGraph(Directed, NodeIDOption{})
+	g.SetID("my graph")
+	a := g.Node("a")
+	b := g.Node("node b")
+	g.EdgeWithPorts(a, b, "out port", "n")
+	g.AddToSameRank(a, b)
+	if got, want := flatten(g.String()), `digraph "my graph" {a[label="a"];"node b"[label="node b"];a:"out port"->"node b":n;{rank=same; a;"node b";};}`; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestQuoteID(t *testing.T) {
+	for id, want := range map[string]string{
+		"abc":      `abc`,
+		"_a1":      `_a1`,
+		"1.5":      `1.5`,
+		"-.5":      `-.5`,
+		"1a":       `"1a"`,
+		"a b":      `"a b"`,
+		"node":     `"node"`,
+		`say "hi"`: `"say \"hi\""`,
+		"":         `""`,
+	} {
+		if got := quoteID(id); got != want {
+			t.Errorf("quoteID(%q) got [%v] want [%v]", id, got, want)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	g := NewGraph(Directed, NodeIDOption{})
+	g.SetAttribute("rankdir", "LR")
+	a := g.Node("a").SetAttribute("label", HTML("<B>a</B>"))
+	b := g.Node("b b").SetAttribute("label", Literal(`"left\l"`))
+	cluster := g.Subgraph("Cluster", ClusterOption{})
+	cluster.SetAttribute("color", "blue")
+	c := cluster.Node("c").SetAttribute("tooltip", `quote " and \ backslash`)
+	inner := cluster.Subgraph("inner")
+	d := inner.Node("d").SetAttribute("width", 1.5)
+	a.Edge(b, "ab")
+	g.EdgeWithPorts(b, c, "p", "s")
+	c.Edge(d).Dashed()
+	g.AddToSameRank(a, b)
+
+	parsed, err := ParseString(g.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g.Equal(parsed) {
+		t.Errorf("round-trip not equal\n%s\n%s", g.canonical(), parsed.canonical())
+	}
+	if got, want := parsed.String(), g.String(); got != want {
+		t.Errorf("got\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestEqualDifferences(t *testing.T) {
+	build := func(change func(g *Graph)) *Graph {
+		g := NewGraph(Directed)
+		g.Node("a").Edge(g.Node("b"))
+		change(g)
+		return g
+	}
+	base := build(func(g *Graph) {})
+	if !base.Equal(build(func(g *Graph) {})) {
+		t.Error("expected equal")
+	}
+	for name, change := range map[string]func(g *Graph){
+		"node attribute": func(g *Graph) { g.Node("a").SetAttribute("shape", "box") },
+		"extra edge":     func(g *Graph) { g.Node("b").Edge(g.Node("a")) },
+		"value kind":     func(g *Graph) { g.Node("a").SetAttribute("label", HTML("a")) },
+		"subgraph":       func(g *Graph) { g.Subgraph("x") },
+		"rank":           func(g *Graph) { g.AddToSameRank(g.Node("a")) },
+	} {
+		if base.Equal(build(change)) {
+			t.Errorf("%s: expected not equal", name)
+		}
+	}
+	if base.Equal(nil) {
+		t.Error("expected not equal to nil")
+	}
+	if strings.Contains(base.canonical(), "n1") {
+		t.Error("sequence numbers must not be compared")
+	}
+}
@@ -15,6 +15,7 @@ type Graph struct {
 	isStrict    bool
 	graphType   string
 	seq         int
+	edgeSeq     int
 	nodes       map[string]Node
 	edgesFrom   map[string][]Edge
 	subgraphs   map[string]*Graph
@@ -24,6 +25,19 @@ type Graph struct {
 	sourceRanks map[string][]Node
 	maxRanks    map[string][]Node
 	sinkRanks   map[string][]Node
+	writeIDs    bool
+	// attributes by class name, see DefineClass
+	classes map[string]map[string]interface{}
+	// see NodeDefaults, EdgeDefaults and GraphDefaults
+	nodeDefaults  map[string]interface{}
+	edgeDefaults  map[string]interface{}
+	graphDefaults map[string]interface{}
+	hoistDefaults bool
+	// edges by node seq of source and target, across all subgraphs, in order of creation ; only used by the root graph
+	outIndex map[int][]Edge
+	inIndex  map[int][]Edge
+	// current value of moved, renamed or merged nodes by their seq ; only used by the root graph, see Node.current
+	relocated map[int]Node
 	//
 	nodeInitializer func(Node)
 	edgeInitializer func(Edge)
@@ -43,6 +57,13 @@ func NewGraph(options ...GraphOption) *Graph {
 		sourceRanks:   map[string][]Node{},
 		maxRanks:      map[string][]Node{},
 		sinkRanks:     map[string][]Node{},
+		classes:       map[string]map[string]interface{}{},
+		nodeDefaults:  map[string]interface{}{},
+		edgeDefaults:  map[string]interface{}{},
+		graphDefaults: map[string]interface{}{},
+		outIndex:      map[int][]Edge{},
+		inIndex:       map[int][]Edge{},
+		relocated:     map[int]Node{},
 	}
 	for _, each := range options {
 		each.Apply(graph)
@@ -82,20 +103,33 @@ func (g *Graph) Root() *Graph {
 	return g.parent.Root()
 }
 
+// FindNodeWithLabel returns a node with the label in this graph or its subgraphs (by key, recursively),
+// or else in one of its parents.
 func (g *Graph) FindNodeWithLabel(label string) (Node, bool) {
-	for _, each := range g.nodes {
+	if n, ok := g.findNodeWithLabelBelow(label); ok {
+		return n, true
+	}
+	if g.parent == nil {
+		return Node{id: "void"}, false
+	}
+	return g.parent.FindNodeWithLabel(label)
+}
+
+func (g *Graph) findNodeWithLabelBelow(label string) (Node, bool) {
+	for _, key := range g.sortedNodesKeys() {
+		each := g.nodes[key]
 		if eachLabel, ok := each.attributes["label"]; ok {
 			if eachLabel == label {
 				return each, true
 			}
 		}
 	}
-	// TODO search subgraphs too?
-	if g.parent == nil {
-		return Node{id: "void"}, false
+	for _, key := range g.sortedSubgraphsKeys() {
+		if n, ok := g.subgraphs[key].findNodeWithLabelBelow(label); ok {
+			return n, true
+		}
 	}
-	return g.parent.FindNodeWithLabel(label)
-
+	return Node{}, false
 }
 
 // FindSubgraph returns the subgraph of the graph or one from its parents.
@@ -109,6 +143,29 @@ func (g *Graph) FindSubgraph(id string) (*Graph, bool) {
 	return sub, ok
 }
 
+// Parent returns the graph that contains this subgraph ; nil for the root.
+func (g *Graph) Parent() *Graph {
+	return g.parent
+}
+
+// Key returns the id that was used to create this subgraph with Subgraph ; empty for the root.
+func (g *Graph) Key() string {
+	if g.parent == nil {
+		return ""
+	}
+	for key, each := range g.parent.subgraphs {
+		if each == g {
+			return key
+		}
+	}
+	return ""
+}
+
+// SubgraphKeys returns the sorted keys of the direct subgraphs ; use Subgraph or FindSubgraph to get one.
+func (g *Graph) SubgraphKeys() []string {
+	return g.sortedSubgraphsKeys()
+}
+
 // Subgraph returns the Graph with the given id ; creates one if absent.
 // The label attribute is also set to the id ; use Label() to overwrite it.
 func (g *Graph) Subgraph(id string, options ...GraphOption) *Graph {
@@ -117,6 +174,8 @@ func (g *Graph) Subgraph(id string, options ...GraphOption) *Graph {
 		return sub
 	}
 	sub = NewGraph(Sub)
+	sub.AttributesMap.validation = g.AttributesMap.validation
+	sub.AttributesMap.kind = "subgraph"
 	sub.SetAttribute("label", id) // for consistency with Node creation behavior.
 	sub.id = fmt.Sprintf("s%d", g.nextSeq())
 	for _, each := range options {
@@ -168,7 +227,7 @@ func (g *Graph) Node(id string) Node {
 		id:  id,
 		seq: g.nextSeq(), // create a new, use root sequence
 		AttributesMap: AttributesMap{attributes: map[string]interface{}{
-			"label": id}},
+			"label": id}, validation: g.AttributesMap.validation, kind: "node"},
 		graph: g,
 	}
 	if g.nodeInitializer != nil {
@@ -181,24 +240,32 @@ func (g *Graph) Node(id string) Node {
 
 // DeleteNode deletes a node and all the edges associated to the node
 // Returns false if the node wasn't found, true otherwise
+// The node can be in any (sub)graph of the root ; all its edges and rank group entries are removed too.
 func (g *Graph) DeleteNode(id string) bool {
-	if _, ok := g.findNode(id); ok {
-		// Remove Node
-		delete(g.nodes, id)
-		// Remove all the edges from the Node
-		delete(g.edgesFrom, id)
-		// Remove all the edges to the Node
-		for parent, edgeList := range g.edgesFrom {
-			for i, edge := range edgeList {
-				if edge.to.id == id {
-					g.edgesFrom[parent] = append(g.edgesFrom[parent][:i], g.edgesFrom[parent][i+1:]...)
-					break
-				}
-			}
-		}
-		return true
+	n, ok := g.findNode(id)
+	if !ok {
+		n, ok = g.Root().FindNodeById(id)
+	}
+	if !ok {
+		return false
 	}
-	return false
+	g.Root().deleteNode(n)
+	return true
+}
+
+// deleteNode removes the node, which is stored in this root graph, with its edges and from all rank groups.
+func (g *Graph) deleteNode(n Node) {
+	// Remove Node
+	delete(n.graph.nodes, n.id)
+	delete(g.relocated, n.seq)
+	// Remove all the edges from and to the Node
+	g.updateEdgesOf(n, func(e Edge) (Edge, bool) {
+		return e, false
+	})
+	// Remove the Node from all rank groups
+	g.visitScopes(func(each *Graph) {
+		each.removeFromRanks(n.seq)
+	})
 }
 
 // Edge creates a new edge between two nodes.
@@ -211,16 +278,17 @@ func (g *Graph) Edge(fromNode, toNode Node, labels ...string) Edge {
 // EdgeWithPorts creates a new edge between two nodes with ports.
 // Other functionality are the same
 func (g *Graph) EdgeWithPorts(fromNode, toNode Node, fromNodePort, toNodePort string, labels ...string) Edge {
-	// assume fromNode owner == toNode owner
-	edgeOwner := g
-	if fromNode.graph != toNode.graph { // 1 or 2 are subgraphs
-		edgeOwner = commonParentOf(fromNode.graph, toNode.graph)
-	}
+	fromNode, toNode = fromNode.current(), toNode.current()
+	// the edge is stored in the innermost (sub)graph that contains both nodes
+	edgeOwner := commonParentOf(fromNode.graph, toNode.graph)
+	root := edgeOwner.Root()
+	root.edgeSeq++
 	e := Edge{
 		from:          fromNode,
 		to:            toNode,
-		AttributesMap: AttributesMap{attributes: map[string]interface{}{}},
-		graph:         edgeOwner}
+		AttributesMap: AttributesMap{attributes: map[string]interface{}{}, validation: g.AttributesMap.validation, kind: "edge"},
+		graph:         edgeOwner,
+		seq:           root.edgeSeq}
 	if fromNodePort != "" {
 		e.fromPort = fromNodePort
 	}
@@ -230,33 +298,49 @@ func (g *Graph) EdgeWithPorts(fromNode, toNode Node, fromNodePort, toNodePort st
 	if len(labels) > 0 {
 		e.SetAttribute("label", strings.Join(labels, ","))
 	}
+	if v := root.validation; v != nil {
+		v.checkPorts(e)
+	}
 	if g.edgeInitializer != nil {
 		g.edgeInitializer(e)
 	}
-	edgeOwner.edgesFrom[fromNode.id] = append(edgeOwner.edgesFrom[fromNode.id], e)
+	root.storeEdge(e)
 	return e
 }
 
+// PortEdge creates a new edge between ports of two nodes, such as those returned by a record builder's FieldWithPort.
+// An empty Port attaches to the node itself. Other functionality is the same as Edge.
+func (g *Graph) PortEdge(fromNode Node, fromPort Port, toNode Node, toPort Port, labels ...string) Edge {
+	return g.EdgeWithPorts(fromNode, toNode, string(fromPort), string(toPort), labels...)
+}
+
 // FindEdges finds all edges in the graph that go from the fromNode to the toNode.
 // Otherwise, returns an empty slice.
+// Edges are found in the (sub)graph in which they are stored, independent of the receiver.
 func (g *Graph) FindEdges(fromNode, toNode Node) (found []Edge) {
 	found = make([]Edge, 0)
-	edgeOwner := g
-	if fromNode.graph != toNode.graph {
-		edgeOwner = commonParentOf(fromNode.graph, toNode.graph)
-	}
-	if edges, ok := edgeOwner.edgesFrom[fromNode.id]; ok {
-		for _, e := range edges {
-			if e.to.id == toNode.id {
-				found = append(found, e)
-			}
+	for _, e := range g.Root().outIndex[fromNode.seq] {
+		if e.to.seq == toNode.seq {
+			found = append(found, e)
 		}
 	}
 	return found
 }
 
+// commonParentOf returns the innermost (sub)graph that is, or is a parent of, both graphs.
 func commonParentOf(one *Graph, two *Graph) *Graph {
-	// TODO
+	if one == two {
+		return one
+	}
+	ancestors := map[*Graph]bool{}
+	for each := one; each != nil; each = each.parent {
+		ancestors[each] = true
+	}
+	for each := two; each != nil; each = each.parent {
+		if ancestors[each] {
+			return each
+		}
+	}
 	return one.Root()
 }
 
@@ -298,21 +382,41 @@ func (g *Graph) IndentedWrite(w *IndentWriter) {
 	if g.isStrict && g.graphType != Sub.Name {
 		fmt.Fprintf(w, "strict ")
 	}
-	fmt.Fprintf(w, "%s %s {", g.graphType, g.id)
+	id := g.id
+	if g.Root().writeIDs && len(id) > 0 {
+		id = quoteID(id)
+	}
+	fmt.Fprintf(w, "%s %s {", g.graphType, id)
 	w.NewLineIndentWhile(func() {
+		// defaults, also for the subgraphs
+		writeDefaults(w, "graph", g.graphDefaults)
+		writeDefaults(w, "node", g.nodeDefaults)
+		writeDefaults(w, "edge", g.edgeDefaults)
 		// subgraphs
 		for _, key := range g.sortedSubgraphsKeys() {
 			each := g.subgraphs[key]
 			each.IndentedWrite(w)
 		}
 		// graph attributes
-		appendSortedMap(g.AttributesMap.attributes, false, w)
+		appendSortedMap(g.resolvedAttributes(g.AttributesMap.attributes), false, w)
 		w.NewLine()
+		hoist := g.Root().hoistDefaults
 		// graph nodes
-		for _, key := range g.sortedNodesKeys() {
+		nodeKeys := g.sortedNodesKeys()
+		nodeAttributes := make([]map[string]interface{}, len(nodeKeys))
+		for i, key := range nodeKeys {
+			nodeAttributes[i] = g.resolvedAttributes(g.nodes[key].attributes)
+		}
+		sharedNodeAttributes := map[string]interface{}{}
+		if hoist {
+			// written after the subgraphs such that these do not inherit them
+			sharedNodeAttributes = sharedAttributes(nodeAttributes)
+			writeDefaults(w, "node", sharedNodeAttributes)
+		}
+		for i, key := range nodeKeys {
 			each := g.nodes[key]
-			fmt.Fprintf(w, "n%d", each.seq)
-			appendSortedMap(each.attributes, true, w)
+			fmt.Fprint(w, g.nodeName(each))
+			appendSortedMap(withoutAttributes(nodeAttributes[i], sharedNodeAttributes), true, w)
 			fmt.Fprintf(w, ";")
 			w.NewLine()
 		}
@@ -321,66 +425,151 @@ func (g *Graph) IndentedWrite(w *IndentWriter) {
 		if g.graphType == "graph" {
 			denoteEdge = "--"
 		}
-		for _, each := range g.sortedEdgesFromKeys() {
-			all := g.edgesFrom[each]
-			for _, each := range all {
+		edgeAttributes := map[*Edge]map[string]interface{}{}
+		allEdgeAttributes := []map[string]interface{}{}
+		for _, key := range g.sortedEdgesFromKeys() {
+			for i := range g.edgesFrom[key] {
+				each := &g.edgesFrom[key][i]
+				edgeAttributes[each] = g.resolvedAttributes(each.attributes)
+				allEdgeAttributes = append(allEdgeAttributes, edgeAttributes[each])
+			}
+		}
+		sharedEdgeAttributes := map[string]interface{}{}
+		if hoist {
+			sharedEdgeAttributes = sharedAttributes(allEdgeAttributes)
+			writeDefaults(w, "edge", sharedEdgeAttributes)
+		}
+		for _, key := range g.sortedEdgesFromKeys() {
+			all := g.edgesFrom[key]
+			for i := range all {
+				each := &all[i]
+				if v := g.Root().validation; v != nil && v.panics {
+					v.checkPorts(*each)
+				}
 				fromPort := ""
 				if each.fromPort != "" {
-					fromPort = ":" + each.fromPort
+					fromPort = ":" + g.portName(each.fromPort)
 				}
 				toPort := ""
 				if each.toPort != "" {
-					toPort = ":" + each.toPort
+					toPort = ":" + g.portName(each.toPort)
 				}
-				fmt.Fprintf(w, "n%d%s%sn%d%s", each.from.seq, fromPort, denoteEdge, each.to.seq, toPort)
-				appendSortedMap(each.attributes, true, w)
+				fmt.Fprintf(w, "%s%s%s%s%s", g.nodeName(each.from), fromPort, denoteEdge, g.nodeName(each.to), toPort)
+				appendSortedMap(withoutAttributes(edgeAttributes[each], sharedEdgeAttributes), true, w)
 				fmt.Fprint(w, ";")
 				w.NewLine()
 			}
 		}
-		for _, nodes := range g.sameRanks {
-			str := ""
-			for _, n := range nodes {
-				str += fmt.Sprintf("n%d;", n.seq)
-			}
-			fmt.Fprintf(w, "{rank=same; %s};", str)
-			w.NewLine()
+		g.writeRanks(w, "same", g.sameRanks)
+		g.writeRanks(w, "min", g.minRanks)
+		g.writeRanks(w, "source", g.sourceRanks)
+		g.writeRanks(w, "max", g.maxRanks)
+		g.writeRanks(w, "sink", g.sinkRanks)
+	})
+	fmt.Fprintf(w, "}")
+	w.NewLine()
+}
+
+func (g *Graph) writeRanks(w *IndentWriter, rank string, groups map[string][]Node) {
+	for _, nodes := range groups {
+		str := ""
+		for _, n := range nodes {
+			str += g.nodeName(n) + ";"
 		}
-		for _, nodes := range g.minRanks {
-			str := ""
-			for _, n := range nodes {
-				str += fmt.Sprintf("n%d;", n.seq)
+		fmt.Fprintf(w, "{rank=%s; %s};", rank, str)
+		w.NewLine()
+	}
+}
+
+// nodeName returns the name used for a node in the DOT output.
+// This is the generated n<seq> unless the NodeIDOption was given to the root graph.
+func (g *Graph) nodeName(n Node) string {
+	if g.Root().writeIDs {
+		return quoteID(n.id)
+	}
+	return fmt.Sprintf("n%d", n.seq)
+}
+
+// portName returns the port (with optional compass point) as written in the DOT output.
+func (g *Graph) portName(port string) string {
+	if !g.Root().writeIDs {
+		return port
+	}
+	parts := strings.Split(port, ":")
+	for i, each := range parts {
+		parts[i] = quoteID(each)
+	}
+	return strings.Join(parts, ":")
+}
+
+// quoteID returns the id as is if it is a valid DOT identifier or numeral ; otherwise a quoted string.
+func quoteID(id string) string {
+	if isPlainID(id) {
+		return id
+	}
+	return quoteString(id)
+}
+
+// quoteString returns the text, such as an identifier, as a DOT quoted string.
+// Only double quotes are escaped ; backslashes are kept as is so escape sequences such as \l reach Graphviz.
+// An odd run of backslashes before a double quote or at the end is padded so it does not escape that quote.
+// String attribute values are quoted with their backslashes escaped instead ; use a Literal for escape sequences.
+func quoteString(text string) string {
+	b := new(strings.Builder)
+	b.WriteRune('"')
+	backslashes := 0
+	for _, r := range text {
+		if r == '"' {
+			if backslashes%2 == 1 {
+				b.WriteRune('\\')
 			}
-			fmt.Fprintf(w, "{rank=min; %s};", str)
-			w.NewLine()
+			b.WriteRune('\\')
 		}
-		for _, nodes := range g.sourceRanks {
-			str := ""
-			for _, n := range nodes {
-				str += fmt.Sprintf("n%d;", n.seq)
-			}
-			fmt.Fprintf(w, "{rank=source; %s};", str)
-			w.NewLine()
+		if r == '\\' {
+			backslashes++
+		} else {
+			backslashes = 0
 		}
-		for _, nodes := range g.maxRanks {
-			str := ""
-			for _, n := range nodes {
-				str += fmt.Sprintf("n%d;", n.seq)
+		b.WriteRune(r)
+	}
+	if backslashes%2 == 1 {
+		b.WriteRune('\\')
+	}
+	b.WriteRune('"')
+	return b.String()
+}
+
+func isPlainID(id string) bool {
+	if len(id) == 0 {
+		return false
+	}
+	switch strings.ToLower(id) {
+	case "node", "edge", "graph", "digraph", "subgraph", "strict":
+		return false
+	}
+	first := []rune(id)[0]
+	if isLetter(first) {
+		for _, r := range id {
+			if !isLetter(r) && !isDigit(r) {
+				return false
 			}
-			fmt.Fprintf(w, "{rank=max; %s};", str)
-			w.NewLine()
 		}
-		for _, nodes := range g.sinkRanks {
-			str := ""
-			for _, n := range nodes {
-				str += fmt.Sprintf("n%d;", n.seq)
-			}
-			fmt.Fprintf(w, "{rank=sink; %s};", str)
-			w.NewLine()
+		return true
+	}
+	// numeral: [-]?(.[0-9]+ | [0-9]+(.[0-9]*)?)
+	digits, dots := 0, 0
+	for i, r := range id {
+		switch {
+		case r == '-' && i == 0:
+		case r == '.':
+			dots++
+		case isDigit(r):
+			digits++
+		default:
+			return false
 		}
-	})
-	fmt.Fprintf(w, "}")
-	w.NewLine()
+	}
+	return digits > 0 && dots <= 1
 }
 
 func appendSortedMap(m map[string]interface{}, mustBracket bool, b io.Writer) {
@@ -406,15 +595,7 @@ func appendSortedMap(m map[string]interface{}, mustBracket bool, b io.Writer) {
 				fmt.Fprintf(b, ";")
 			}
 		}
-		if html, isHTML := m[k].(HTML); isHTML {
-			fmt.Fprintf(b, "%s=<%s>", k, html)
-		} else if literal, isLiteral := m[k].(Literal); isLiteral {
-			fmt.Fprintf(b, "%s=%s", k, literal)
-		} else if str, ok := m[k].(string); ok {
-			fmt.Fprintf(b, "%s=%q", k, str)
-		} else {
-			fmt.Fprintf(b, "%s=\"%v\"", k, m[k])
-		}
+		fmt.Fprintf(b, "%s=%s", k, attributeValue(m[k]))
 		first = false
 	}
 	if mustBracket {
@@ -424,6 +605,18 @@ func appendSortedMap(m map[string]interface{}, mustBracket bool, b io.Writer) {
 	}
 }
 
+// attributeValue returns the value as written in the DOT output.
+func attributeValue(v interface{}) string {
+	if html, isHTML := v.(HTML); isHTML {
+		return fmt.Sprintf("<%s>", html)
+	} else if literal, isLiteral := v.(Literal); isLiteral {
+		return string(literal)
+	} else if str, ok := v.(string); ok {
+		return fmt.Sprintf("%q", str)
+	}
+	return quoteString(fmt.Sprint(v))
+}
+
 // VisitNodes visits all nodes recursively
 func (g *Graph) VisitNodes(callback func(node Node) (done bool)) {
 	for _, node := range g.nodes {
@@ -492,14 +685,23 @@ func (g *Graph) DeepCopy() *Graph {
 	copy.isStrict = g.isStrict
 	copy.graphType = g.graphType
 	copy.seq = g.seq
+	copy.edgeSeq = g.edgeSeq
 	copy.parent = g.parent
+	copy.writeIDs = g.writeIDs
+	copy.hoistDefaults = g.hoistDefaults
 
-	copy.AttributesMap = AttributesMap{attributes: g.Attributes()}
+	copy.AttributesMap = AttributesMap{attributes: g.Attributes(), validation: g.AttributesMap.validation, kind: g.AttributesMap.kind}
+	for name := range g.classes {
+		copy.classes[name], _ = g.ClassAttributes(name)
+	}
+	copy.nodeDefaults = copyAttributes(g.nodeDefaults)
+	copy.edgeDefaults = copyAttributes(g.edgeDefaults)
+	copy.graphDefaults = copyAttributes(g.graphDefaults)
 
 	copy.nodes = make(map[string]Node, len(g.nodes))
 	for id, node := range g.nodes {
 		copy.nodes[id] = Node{
-			AttributesMap: AttributesMap{attributes: node.Attributes()},
+			AttributesMap: AttributesMap{attributes: node.Attributes(), validation: node.validation, kind: node.kind},
 			graph:         copy,
 			id:            node.id,
 			seq:           node.seq,
@@ -511,12 +713,13 @@ func (g *Graph) DeepCopy() *Graph {
 		newEdges := make([]Edge, len(edges))
 		for i, edge := range edges {
 			newEdges[i] = Edge{
-				AttributesMap: AttributesMap{attributes: edge.Attributes()},
+				AttributesMap: AttributesMap{attributes: edge.Attributes(), validation: edge.validation, kind: edge.kind},
 				graph:         copy,
-				from:          copy.nodes[edge.from.id],
-				to:            copy.nodes[edge.to.id],
+				from:          copy.nodeOrSelf(edge.from),
+				to:            copy.nodeOrSelf(edge.to),
 				fromPort:      edge.fromPort,
 				toPort:        edge.toPort,
+				seq:           edge.seq,
 			}
 		}
 		copy.edgesFrom[from] = newEdges
@@ -543,7 +746,7 @@ func (g *Graph) DeepCopy() *Graph {
 	for _, rank := range sameRankKeys {
 		newNodes := make([]Node, len(g.sameRanks[rank]))
 		for i, node := range g.sameRanks[rank] {
-			newNodes[i] = copy.nodes[node.id]
+			newNodes[i] = copy.nodeOrSelf(node)
 		}
 		copy.sameRanks[rank] = newNodes
 	}
@@ -556,7 +759,7 @@ func (g *Graph) DeepCopy() *Graph {
 	for _, rank := range minRankKeys {
 		newNodes := make([]Node, len(g.minRanks[rank]))
 		for i, node := range g.minRanks[rank] {
-			newNodes[i] = copy.nodes[node.id]
+			newNodes[i] = copy.nodeOrSelf(node)
 		}
 		copy.minRanks[rank] = newNodes
 	}
@@ -569,7 +772,7 @@ func (g *Graph) DeepCopy() *Graph {
 	for _, rank := range sourceRankKeys {
 		newNodes := make([]Node, len(g.sourceRanks[rank]))
 		for i, node := range g.sourceRanks[rank] {
-			newNodes[i] = copy.nodes[node.id]
+			newNodes[i] = copy.nodeOrSelf(node)
 		}
 		copy.sourceRanks[rank] = newNodes
 	}
@@ -582,7 +785,7 @@ func (g *Graph) DeepCopy() *Graph {
 	for _, rank := range maxRankKeys {
 		newNodes := make([]Node, len(g.maxRanks[rank]))
 		for i, node := range g.maxRanks[rank] {
-			newNodes[i] = copy.nodes[node.id]
+			newNodes[i] = copy.nodeOrSelf(node)
 		}
 		copy.maxRanks[rank] = newNodes
 	}
@@ -595,7 +798,7 @@ func (g *Graph) DeepCopy() *Graph {
 	for _, rank := range sinkRankKeys {
 		newNodes := make([]Node, len(g.sinkRanks[rank]))
 		for i, node := range g.sinkRanks[rank] {
-			newNodes[i] = copy.nodes[node.id]
+			newNodes[i] = copy.nodeOrSelf(node)
 		}
 		copy.sinkRanks[rank] = newNodes
 	}
@@ -603,5 +806,48 @@ func (g *Graph) DeepCopy() *Graph {
 	copy.nodeInitializer = g.nodeInitializer
 	copy.edgeInitializer = g.edgeInitializer
 
+	if g.parent == nil {
+		// the copy records its own attribute errors
+		if g.AttributesMap.validation != nil {
+			copy.setValidation(g.AttributesMap.validation.copy())
+		}
+		// edges and ranks can refer to nodes of other subgraphs
+		copy.relinkNodes()
+	}
 	return copy
 }
+
+// nodeOrSelf returns the node with the same id of this graph ; the argument if absent.
+func (g *Graph) nodeOrSelf(n Node) Node {
+	if local, ok := g.nodes[n.id]; ok {
+		return local
+	}
+	return n
+}
+
+// relinkNodes replaces the nodes referred to by edges and ranks by the nodes of this graph tree.
+func (g *Graph) relinkNodes() {
+	nodes := map[int]Node{}
+	for _, each := range g.FindNodes() {
+		nodes[each.seq] = each
+	}
+	relink := func(n Node) Node {
+		if local, ok := nodes[n.seq]; ok {
+			return local
+		}
+		return n
+	}
+	g.rewriteEdges(func(e Edge) (Edge, bool) {
+		e.from, e.to = relink(e.from), relink(e.to)
+		return e, true
+	})
+	g.visitScopes(func(each *Graph) {
+		for _, groups := range each.rankGroups() {
+			for _, list := range groups {
+				for i, n := range list {
+					list[i] = relink(n)
+				}
+			}
+		}
+	})
+}
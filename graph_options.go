@@ -10,6 +10,15 @@ func (o ClusterOption) Apply(g *Graph) {
 	g.beCluster()
 }
 
+// NodeIDOption makes the graph write the identifiers of nodes (quoted when needed)
+// instead of the generated n<seq> names. Subgraph identifiers are quoted as well.
+// Only applicable to the root graph.
+type NodeIDOption struct{}
+
+func (o NodeIDOption) Apply(g *Graph) {
+	g.writeIDs = true
+}
+
 var (
 	Strict     = GraphTypeOption{"strict"} // only for graph and digraph, not for subgraph
 	Undirected = GraphTypeOption{"graph"}
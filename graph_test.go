@@ -178,6 +178,22 @@ func TestSubgraphClusterOption(t *testing.T) {
 	}
 }
 
+func TestSubgraphParentAndKey(t *testing.T) {
+	di := NewGraph(Directed)
+	sub := di.Subgraph("test", ClusterOption{})
+	inner := sub.Subgraph("inner")
+	if got, want := inner.Parent(), sub; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := inner.Key()+" "+sub.Key()+" "+di.Key(), "inner test "; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	di.Subgraph("other")
+	if got, want := strings.Join(di.SubgraphKeys(), " "), "other test"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
 func TestEdgeLabel(t *testing.T) {
 	di := NewGraph(Directed)
 	n1 := di.Node("e1")
@@ -356,6 +372,61 @@ func TestGraphCommonParent(t *testing.T) {
 	}
 }
 
+func TestGraphNestedCommonParent(t *testing.T) {
+	di := NewGraph(Directed)
+	outer := di.Subgraph("outer")
+	left := outer.Subgraph("left")
+	right := outer.Subgraph("right")
+	a := left.Node("a")
+	b := right.Node("b")
+	c := left.Node("c")
+	// created from the root but stored in the innermost common subgraph
+	di.Edge(a, b)
+	di.Edge(a, c)
+	if got, want := commonParentOf(left, right), outer; got != want {
+		t.Errorf("got [%v] want [%v]", got.ID(), want.ID())
+	}
+	if got, want := len(outer.edgesFrom["a"]), 1; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := len(left.edgesFrom["a"]), 1; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := len(di.edgesFrom), 0; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := len(right.FindEdges(a, b)), 1; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := len(a.EdgesTo(c)), 1; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := flatten(di.String()), `digraph  {subgraph s1 {subgraph s2 {label="left";n4[label="a"];n6[label="c"];n4->n6;}subgraph s3 {label="right";n5[label="b"];}label="outer";n4->n5;}}`; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestDeleteNodeInSubgraphs(t *testing.T) {
+	di := NewGraph(Directed)
+	sub := di.Subgraph("sub")
+	a := di.Node("a")
+	b := sub.Node("b")
+	c := sub.Node("c")
+	a.Edge(b)
+	c.Edge(b)
+	c.Edge(b)
+	a.Edge(c)
+	if !sub.DeleteNode("b") {
+		t.Fatal("expected deleted")
+	}
+	if _, ok := di.FindNodeById("b"); ok {
+		t.Error("b not deleted")
+	}
+	if got, want := flatten(di.String()), `digraph  {subgraph s1 {label="sub";n4[label="c"];}n2[label="a"];n2->n4;}`; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
 func TestReverseEdge(t *testing.T) {
 	di := NewGraph(Directed)
 	if !di.IsDirected() {
@@ -414,6 +485,14 @@ func TestFindNodeWithLabel(t *testing.T) {
 	if ok {
 		t.Fail()
 	}
+
+	n3, ok := di.FindNodeWithLabel("C")
+	if !ok {
+		t.Fatal("expected node in subgraph")
+	}
+	if got, want := n3.ID(), "C"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
 }
 
 func TestNodeGetAttributesCopy(t *testing.T) {
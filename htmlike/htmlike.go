@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"html"
 	"io"
+
+	"github.com/eristocrates/dot"
 )
 
 // HtmLike represents a Graphviz HTML-like label, enclosed in <...>.
@@ -192,6 +194,12 @@ func (t *Table) SetTooltip(v string) *Table       { t.Tooltip = v; return t }
 func (t *Table) SetValign(v HtmLikeValign) *Table { t.Valign = v; return t }
 func (t *Table) SetWidth(v uint) *Table           { t.Width = &v; return t }
 
+// DefinePort sets the PORT of the table and returns it for connecting edges, see dot.Graph.PortEdge.
+func (t *Table) DefinePort(name string) dot.Port {
+	t.Port = name
+	return dot.Port(name)
+}
+
 // AppendChildren adds children to the table.
 func (t *Table) AppendChildren(children ...HtmLikeElement) *Table {
 	t.Children = append(t.Children, children...)
@@ -336,6 +344,13 @@ func (td *TD) SetTooltip(v string) *TD       { td.Tooltip = v; return td }
 func (td *TD) SetValign(v HtmLikeValign) *TD { td.Valign = v; return td }
 func (td *TD) SetWidth(v uint) *TD           { td.Width = &v; return td }
 
+// DefinePort sets the PORT of the table cell and returns it for connecting edges, see dot.Graph.PortEdge.
+// Use SetPort instead to continue building the cell.
+func (td *TD) DefinePort(name string) dot.Port {
+	td.Port = name
+	return dot.Port(name)
+}
+
 // AppendChildren adds children to the table cell.
 func (td *TD) AppendChildren(children ...HtmLikeElement) *TD {
 	td.Children = append(td.Children, children...)
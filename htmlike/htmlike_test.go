@@ -165,3 +165,19 @@ func TestExampleHtmLike(t *testing.T) {
 	os.WriteFile("testing/TestExampleHtmlike.dot", []byte(g.String()), os.ModePerm)
 
 }
+
+func TestDefinePort(t *testing.T) {
+	g := dot.NewGraph(dot.Directed)
+	cell := NewTD(NewText("in"))
+	in := cell.DefinePort("in")
+	table := NewTable(NewTR(cell))
+	out := table.DefinePort("all")
+	n := g.Node("n").SetAttribute("shape", "plain").SetAttribute("label", dot.HTML(NewHtmLike(table).String()))
+	n.PortEdge(out, n, in.Compass(dot.W))
+	if got := g.PortErrors(); len(got) != 0 {
+		t.Errorf("unexpected errors %v", got)
+	}
+	if ports, _ := n.Ports(); len(ports) != 2 {
+		t.Errorf("got %v want [all in]", ports)
+	}
+}
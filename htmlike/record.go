@@ -0,0 +1,115 @@
+package htmlike
+
+import (
+	"math"
+	"strings"
+
+	"github.com/eristocrates/dot"
+)
+
+// NewRecordTable returns a table with the structure of record fields, such as from dot.ParseRecordLabel
+// or the Fields of a record builder. Each text field is a cell with the port, lines and style of the field.
+// Fields are laid out horizontally, unless vertical (as records are with rankdir LR or RL) ; nested fields flip the direction.
+func NewRecordTable(fields []dot.RecordField, vertical bool) *Table {
+	return recordTable(fields, vertical).SetBorder(0)
+}
+
+func recordTable(fields []dot.RecordField, vertical bool) *Table {
+	table := NewTable().SetCellBorder(1).SetCellSpacing(0)
+	row := NewTR()
+	for _, each := range fields {
+		cell := recordCell(each, vertical)
+		if vertical {
+			table.AppendChildren(NewTR(cell))
+		} else {
+			row.AppendChildren(cell)
+		}
+	}
+	if !vertical {
+		table.AppendChildren(row)
+	}
+	return table
+}
+
+// recordCell returns the cell for a text field or a cell with a table for nested fields.
+func recordCell(field dot.RecordField, vertical bool) *TD {
+	if field.IsNested() {
+		return NewTD(recordTable(field.Fields, !vertical).SetBorder(0)).SetBorder(0).SetCellPadding(0)
+	}
+	cell := NewTD()
+	if len(field.Port) > 0 {
+		cell.SetPort(field.Port)
+	}
+	style := field.Style
+	if len(style.BGColor) > 0 {
+		cell.SetBGColor(string(style.BGColor))
+	}
+	content := recordLines(field.Text)
+	if style.Bold {
+		content = []HtmLikeElement{NewB(content...)}
+	}
+	if style.Italic {
+		content = []HtmLikeElement{NewI(content...)}
+	}
+	if len(style.FontColor) > 0 || len(style.FontName) > 0 || style.FontSize > 0 {
+		font := NewFONT(content...).SetColor(string(style.FontColor)).SetFace(style.FontName)
+		if style.FontSize > 0 {
+			font.SetPointSize(int(math.Round(style.FontSize)))
+		}
+		content = []HtmLikeElement{font}
+	}
+	return cell.AppendChildren(content...)
+}
+
+// recordLines returns the text of a record field with its line breaks (\n, \l and \r) as aligned BR elements.
+// Other backslash sequences, such as \\ or \o, are the escaped character, as Graphviz reads them in a record.
+func recordLines(text string) []HtmLikeElement {
+	elements := []HtmLikeElement{}
+	line := new(strings.Builder)
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if c != '\\' || i+1 == len(text) {
+			line.WriteByte(c)
+			continue
+		}
+		i++
+		switch text[i] {
+		case 'n':
+			elements = append(elements, NewText(line.String()), NewBR())
+		case 'l':
+			elements = append(elements, NewText(line.String()), NewBR(AlignLEFT))
+		case 'r':
+			elements = append(elements, NewText(line.String()), NewBR(AlignRIGHT))
+		default:
+			line.WriteByte(text[i])
+			continue
+		}
+		line.Reset()
+	}
+	if line.Len() > 0 || len(elements) == 0 {
+		elements = append(elements, NewText(line.String()))
+	}
+	return elements
+}
+
+// SetTableLabel sets the label of the node to the table and its shape to "plain", so that only the table is drawn.
+func SetTableLabel(n dot.Node, table *Table) dot.Node {
+	return n.SetAttribute("shape", "plain").SetAttribute("label", dot.HTML(NewHtmLike(table).String()))
+}
+
+// ToHTMLLike replaces the record label of the node by an HTML-like table with the same fields and ports,
+// so that edges to the ports of the node remain valid. An Mrecord becomes a table with rounded corners.
+// Returns an error if the node has no record shape or its label is invalid.
+func ToHTMLLike(n dot.Node) error {
+	fields, err := n.RecordFields()
+	if err != nil {
+		return err
+	}
+	rankdir, _ := n.Graph().Root().Value("rankdir").(string)
+	table := NewRecordTable(fields, rankdir == "LR" || rankdir == "RL")
+	if shape, _ := n.EffectiveAttributes()["shape"].(string); strings.EqualFold(shape, "Mrecord") {
+		table.SetStyle("ROUNDED").SetBorder(1)
+	}
+	SetTableLabel(n, table)
+	return nil
+}
@@ -0,0 +1,48 @@
+package htmlike
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eristocrates/dot"
+)
+
+func TestToHTMLLike(t *testing.T) {
+	g := dot.NewGraph(dot.Directed)
+	r := g.Node("r").SetAttribute("shape", "Mrecord").Label(`<in> a\lb\l|{<x> x|y}|c\<d|\ok\\`)
+	other := g.Node("o")
+	g.PortEdge(other, "", r, "in")
+	g.PortEdge(r, dot.Port("x").Compass(dot.E), other, "")
+	if err := ToHTMLLike(r); err != nil {
+		t.Fatal(err)
+	}
+	label, _ := r.Attribute("label").(dot.HTML)
+	if got, want := strings.ReplaceAll(string(label), "\n", ""), `<TABLE BORDER="1" CELLBORDER="1" CELLSPACING="0" STYLE="ROUNDED"><TR><TD PORT="in">a<BR ALIGN="LEFT"/>b<BR ALIGN="LEFT"/></TD><TD BORDER="0" CELLPADDING="0"><TABLE BORDER="0" CELLBORDER="1" CELLSPACING="0"><TR><TD PORT="x">x</TD></TR><TR><TD>y</TD></TR></TABLE></TD><TD>c&lt;d</TD><TD>ok\</TD></TR></TABLE>`; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := r.Attribute("shape"), "plain"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if errs := g.PortErrors(); len(errs) != 0 {
+		t.Errorf("unexpected errors %v", errs)
+	}
+	if err := ToHTMLLike(other); err == nil {
+		t.Error("expected error for a node without record shape")
+	}
+}
+
+func TestNewRecordTableWithStyles(t *testing.T) {
+	g := dot.NewGraph(dot.Directed)
+	n := g.Node("n")
+	rb := n.NewRecordBuilder()
+	rb.FieldWithId("id", "id", dot.FieldStyle{BGColor: "lightblue", Bold: true})
+	rb.Field("name", dot.FieldAlignLeft, dot.FieldStyle{FontColor: "gray", FontSize: 9.6, Italic: true})
+	SetTableLabel(n, NewRecordTable(rb.Fields(), true))
+	label, _ := n.Attribute("label").(dot.HTML)
+	if got, want := strings.ReplaceAll(string(label), "\n", ""), `<TABLE BORDER="0" CELLBORDER="1" CELLSPACING="0"><TR><TD BGCOLOR="lightblue" PORT="id"><B>id</B></TD></TR><TR><TD><FONT COLOR="gray" POINT-SIZE="10"><I>name<BR ALIGN="LEFT"/></I></FONT></TD></TR></TABLE>`; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if ports, _ := n.Ports(); len(ports) != 1 || ports[0] != "id" {
+		t.Errorf("got %v want [id]", ports)
+	}
+}
@@ -0,0 +1,262 @@
+// Command attrgen generates attributes_gen.go of package dot from attributes.txt
+// and colors_gen.go from colors.txt.
+//
+// Run it from the root of the module with:
+//
+//	go generate
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type enum struct {
+	goType, valueType, prefix string
+	values                    []string
+}
+
+type attribute struct {
+	name, usedBy, valueType string
+	// setters are kind (N, E or G) and method name pairs
+	setters [][2]string
+}
+
+type brewerFamily struct {
+	name                   string
+	minClasses, maxClasses int
+	colors                 []string
+}
+
+func main() {
+	enums, attributes, err := read(filepath.Join("internal", "attrgen", "attributes.txt"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	out := new(bytes.Buffer)
+	write(out, enums, attributes)
+	writeFormatted("attributes_gen.go", out)
+
+	named, families, err := readColors(filepath.Join("internal", "attrgen", "colors.txt"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	out = new(bytes.Buffer)
+	writeColors(out, named, families)
+	writeFormatted("colors_gen.go", out)
+}
+
+func writeFormatted(name string, out *bytes.Buffer) {
+	formatted, err := format.Source(out.Bytes())
+	if err != nil {
+		log.Fatalf("formatting generated source of %s: %v", name, err)
+	}
+	if err := os.WriteFile(name, formatted, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func read(name string) ([]enum, []attribute, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+	enums := []enum{}
+	attributes := []attribute{}
+	scanner := bufio.NewScanner(f)
+	line := 0
+	for scanner.Scan() {
+		line++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		switch {
+		case fields[0] == "enum" && len(fields) > 4:
+			enums = append(enums, enum{goType: fields[1], valueType: fields[2], prefix: strings.TrimPrefix(fields[3], "-"), values: fields[4:]})
+		case fields[0] == "attr" && len(fields) > 3:
+			a := attribute{name: fields[1], usedBy: fields[2], valueType: fields[3]}
+			for _, each := range fields[4:] {
+				kind, method, ok := strings.Cut(each, ":")
+				if !ok || !strings.Contains("NEG", kind) {
+					return nil, nil, fmt.Errorf("%s:%d: invalid setter %q", name, line, each)
+				}
+				a.setters = append(a.setters, [2]string{kind, method})
+			}
+			attributes = append(attributes, a)
+		default:
+			return nil, nil, fmt.Errorf("%s:%d: invalid line", name, line)
+		}
+	}
+	return enums, attributes, scanner.Err()
+}
+
+func write(out *bytes.Buffer, enums []enum, attributes []attribute) {
+	fmt.Fprintln(out, "// Code generated by internal/attrgen from internal/attrgen/attributes.txt; DO NOT EDIT.")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "package dot")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "// attributeCatalogue has all Graphviz attributes by name.")
+	fmt.Fprintln(out, "var attributeCatalogue = map[string]AttributeInfo{")
+	for _, each := range attributes {
+		fmt.Fprintf(out, "%q: {Name: %q, UsedBy: %q, Type: %q},\n", each.name, each.name, each.usedBy, each.valueType)
+	}
+	fmt.Fprintln(out, "}")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "// attributeEnums has the valid values by value type.")
+	fmt.Fprintln(out, "var attributeEnums = map[string][]string{")
+	for _, each := range enums {
+		quoted := []string{}
+		for _, v := range each.values {
+			quoted = append(quoted, fmt.Sprintf("%q", v))
+		}
+		fmt.Fprintf(out, "%q: {%s},\n", each.valueType, strings.Join(quoted, ", "))
+	}
+	fmt.Fprintln(out, "}")
+	goTypes := map[string]string{}
+	for _, each := range enums {
+		goTypes[each.valueType] = each.goType
+		fmt.Fprintln(out)
+		fmt.Fprintf(out, "// %s is a value of the Graphviz type %s.\n", each.goType, each.valueType)
+		fmt.Fprintf(out, "type %s string\n\n", each.goType)
+		fmt.Fprintln(out, "const (")
+		for _, v := range each.values {
+			fmt.Fprintf(out, "%s %s = %q\n", constantName(each.prefix, v), each.goType, v)
+		}
+		fmt.Fprintln(out, ")")
+	}
+	type setter struct {
+		kind, method string
+		a            attribute
+	}
+	setters := []setter{}
+	for _, a := range attributes {
+		for _, each := range a.setters {
+			setters = append(setters, setter{kind: each[0], method: each[1], a: a})
+		}
+	}
+	order := map[string]int{"N": 0, "E": 1, "G": 2}
+	sort.SliceStable(setters, func(i, j int) bool { return order[setters[i].kind] < order[setters[j].kind] })
+	for _, each := range setters {
+		writeSetter(out, each.kind, each.method, each.a, goTypes)
+	}
+}
+
+// writeSetter writes a typed method that sets the attribute.
+func writeSetter(out *bytes.Buffer, kind, method string, a attribute, goTypes map[string]string) {
+	receiver := map[string]string{"N": "n Node", "E": "e Edge", "G": "g *Graph"}[kind]
+	result := map[string]string{"N": "Node", "E": "Edge", "G": "*Graph"}[kind]
+	param, value := "v string", "v"
+	switch a.valueType {
+	case "double":
+		param = "v float64"
+	case "int":
+		param = "v int"
+	case "bool":
+		param = "v bool"
+	case "style":
+		param, value = "styles ...Style", "joinStyles(styles)"
+	case "color":
+		param, value = "v Color", "string(v)"
+	default:
+		if goType, ok := goTypes[a.valueType]; ok {
+			param, value = "v "+goType, "string(v)"
+		}
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "// %s sets the %q attribute.\n", method, a.name)
+	fmt.Fprintf(out, "func (%s) %s(%s) %s {\n", receiver, method, param, result)
+	if kind == "G" {
+		fmt.Fprintf(out, "g.SetAttribute(%q, %s)\n", a.name, value)
+		fmt.Fprintln(out, "return g")
+	} else {
+		fmt.Fprintf(out, "return %c.SetAttribute(%q, %s)\n", receiver[0], a.name, value)
+	}
+	fmt.Fprintln(out, "}")
+}
+
+// constantName returns the prefix followed by the value with its first letter in upper case.
+func constantName(prefix, value string) string {
+	return prefix + strings.ToUpper(value[:1]) + value[1:]
+}
+
+// readColors returns the named colors by scheme (with names in order of the file) and the Brewer families.
+func readColors(name string) (map[string][][2]string, []brewerFamily, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+	named := map[string][][2]string{}
+	families := []brewerFamily{}
+	scanner := bufio.NewScanner(f)
+	line := 0
+	for scanner.Scan() {
+		line++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		switch {
+		case (fields[0] == "x11" || fields[0] == "svg") && len(fields) == 3:
+			named[fields[0]] = append(named[fields[0]], [2]string{fields[1], fields[2]})
+		case fields[0] == "brewer" && len(fields) >= 4:
+			family := brewerFamily{name: fields[1], colors: fields[4:]}
+			if _, err := fmt.Sscan(fields[2]+" "+fields[3], &family.minClasses, &family.maxClasses); err != nil {
+				return nil, nil, fmt.Errorf("%s:%d: %v", name, line, err)
+			}
+			if len(family.colors) > 0 && len(family.colors) != family.maxClasses {
+				return nil, nil, fmt.Errorf("%s:%d: expected %d colors", name, line, family.maxClasses)
+			}
+			families = append(families, family)
+		default:
+			return nil, nil, fmt.Errorf("%s:%d: invalid line", name, line)
+		}
+	}
+	return named, families, scanner.Err()
+}
+
+func writeColors(out *bytes.Buffer, named map[string][][2]string, families []brewerFamily) {
+	fmt.Fprintln(out, "// Code generated by internal/attrgen from internal/attrgen/colors.txt; DO NOT EDIT.")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "package dot")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "// namedColors has the 0xRRGGBB values of the color names by scheme.")
+	fmt.Fprintln(out, "var namedColors = map[string]map[string]uint32{")
+	schemes := []string{}
+	for each := range named {
+		schemes = append(schemes, each)
+	}
+	sort.Strings(schemes)
+	for _, scheme := range schemes {
+		fmt.Fprintf(out, "%q: {\n", scheme)
+		for _, each := range named[scheme] {
+			fmt.Fprintf(out, "%q: 0x%s,\n", each[0], each[1])
+		}
+		fmt.Fprintln(out, "},")
+	}
+	fmt.Fprintln(out, "}")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "// brewerFamilies has the Brewer color scheme families by name.")
+	fmt.Fprintln(out, "var brewerFamilies = map[string]brewerFamily{")
+	for _, each := range families {
+		colors := []string{}
+		for _, c := range each.colors {
+			colors = append(colors, "0x"+c)
+		}
+		list := "nil"
+		if len(colors) > 0 {
+			list = "[]uint32{" + strings.Join(colors, ", ") + "}"
+		}
+		fmt.Fprintf(out, "%q: {minClasses: %d, maxClasses: %d, colors: %s},\n", each.name, each.minClasses, each.maxClasses, list)
+	}
+	fmt.Fprintln(out, "}")
+}
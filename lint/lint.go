@@ -0,0 +1,439 @@
+// Package lint inspects a dot.Graph, including all its subgraphs, and reports problems as diagnostics.
+//
+// Each Diagnostic has a severity, the rule that reported it, the element it is about and a suggested fix.
+// Diagnostics are reported in order of the graph: attributes, defaults and classes of a (sub)graph first,
+// then its rank groups, nodes (by id), edges and subgraphs (by key).
+//
+//	import "github.com/eristocrates/dot/lint"
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/eristocrates/dot"
+)
+
+// Severity tells how likely a diagnostic is a mistake.
+type Severity int
+
+const (
+	// Info is about something that is valid but probably not intended.
+	Info Severity = iota
+	// Warning is about something that Graphviz ignores or handles differently than expected.
+	Warning
+	// Error is about something that Graphviz rejects or renders wrongly.
+	Error
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Info:
+		return "info"
+	case Warning:
+		return "warning"
+	case Error:
+		return "error"
+	}
+	return fmt.Sprintf("severity(%d)", int(s))
+}
+
+// Rules that report diagnostics ; see Options.Ignore.
+const (
+	RuleUnknownAttribute = "unknown-attribute"
+	RuleAttributeKind    = "attribute-kind"
+	RuleInvalidValue     = "invalid-value"
+	RuleUnknownPort      = "unknown-port"
+	RuleForeignRank      = "foreign-rank"
+	RuleClusterPrefix    = "cluster-prefix"
+	RuleEmptyLabel       = "empty-label"
+	RuleDuplicateLabel   = "duplicate-label"
+)
+
+// Diagnostic describes a problem found in a graph.
+type Diagnostic struct {
+	Severity Severity
+	// Rule is one of the Rule constants.
+	Rule string
+	// Element describes where the problem is, such as `node "a"` or `edge "a" -> "b"`.
+	Element string
+	Message string
+	// Suggestion describes how to fix the problem.
+	Suggestion string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s: %s (%s) ; %s", d.Severity, d.Element, d.Message, d.Rule, d.Suggestion)
+}
+
+// Options changes which diagnostics are reported.
+type Options struct {
+	// Allow has the names of other (non Graphviz) attributes that can be set, such as "link" for Mermaid output.
+	Allow []string
+	// Ignore has the rules for which no diagnostics are reported.
+	Ignore []string
+	// MinSeverity is the lowest severity that is reported.
+	MinSeverity Severity
+}
+
+// Lint returns the diagnostics for the graph and all its subgraphs.
+func Lint(g *dot.Graph) []Diagnostic {
+	return LintWithOptions(g, Options{})
+}
+
+// LintWithOptions returns the diagnostics for the graph and all its subgraphs, filtered by the options.
+func LintWithOptions(g *dot.Graph, options Options) []Diagnostic {
+	l := &linter{options: options, directed: g.IsDirected()}
+	l.lintScope(g)
+	l.lintDuplicateLabels(g)
+	return l.diagnostics
+}
+
+type linter struct {
+	options     Options
+	directed    bool
+	diagnostics []Diagnostic
+}
+
+func (l *linter) report(d Diagnostic) {
+	if d.Severity < l.options.MinSeverity || containsString(l.options.Ignore, d.Rule) {
+		return
+	}
+	l.diagnostics = append(l.diagnostics, d)
+}
+
+// lintScope checks the (sub)graph and then its subgraphs.
+func (l *linter) lintScope(g *dot.Graph) {
+	kind := "graph"
+	if g.Parent() != nil {
+		kind = "subgraph"
+	}
+	graphScheme := inheritedColorScheme(g, func(s *dot.Graph) dot.AttributesMap { return s.AttributesMap }, (*dot.Graph).GraphDefaults)
+	l.lintAttributes(kind, describeGraph(g), g.AttributesMap.Attributes(), graphScheme)
+	l.lintCluster(g)
+	nodeDefaults, edgeDefaults, graphDefaults := g.NodeDefaults(), g.EdgeDefaults(), g.GraphDefaults()
+	l.lintAttributes("subgraph", "graph defaults of "+describeGraph(g), graphDefaults.Attributes(),
+		inheritedColorScheme(g, (*dot.Graph).GraphDefaults))
+	l.lintAttributes("node", "node defaults of "+describeGraph(g), nodeDefaults.Attributes(),
+		inheritedColorScheme(g, (*dot.Graph).NodeDefaults))
+	l.lintAttributes("edge", "edge defaults of "+describeGraph(g), edgeDefaults.Attributes(),
+		inheritedColorScheme(g, (*dot.Graph).EdgeDefaults))
+	for _, name := range g.ClassNames() {
+		attributes, _ := g.ClassAttributes(name)
+		l.lintAttributesOf(classKinds(g, name), fmt.Sprintf("class %q of %s", name, describeGraph(g)), attributes,
+			colorScheme(attributes))
+	}
+	l.lintRanks(g)
+	for _, each := range scopeNodes(g) {
+		effective := each.EffectiveAttributes()
+		l.lintAttributes("node", describeNode(each), each.Attributes(), colorScheme(effective))
+		l.lintEmptyLabel(describeNode(each), effective)
+	}
+	for _, each := range scopeEdges(g) {
+		effective := each.EffectiveAttributes()
+		l.lintAttributes("edge", l.describeEdge(each), each.Attributes(), colorScheme(effective))
+		l.lintEmptyLabel(l.describeEdge(each), effective)
+		l.lintPort(each, each.From(), each.FromPort())
+		l.lintPort(each, each.To(), each.ToPort())
+	}
+	for _, key := range g.SubgraphKeys() {
+		sub, _ := g.FindSubgraph(key)
+		l.lintScope(sub)
+	}
+}
+
+// lintAttributes checks the names and values of the attributes of an element of a kind.
+func (l *linter) lintAttributes(kind, element string, attributes map[string]interface{}, colorscheme string) {
+	l.lintAttributesOf([]string{kind}, element, attributes, colorscheme)
+}
+
+// lintAttributesOf checks the names and values of attributes that are used by elements of one or more kinds,
+// such as those of a class. The value is checked for the first kind that uses the attribute ;
+// colors are resolved in the colorscheme of the element, if not empty.
+func (l *linter) lintAttributesOf(kinds []string, element string, attributes map[string]interface{}, colorscheme string) {
+	for _, name := range sortedKeys(attributes) {
+		if containsString(l.options.Allow, name) {
+			continue
+		}
+		value := attributes[name]
+		info, ok := dot.LookupAttribute(name)
+		if !ok {
+			suggestion := "remove it or add it to Options.Allow"
+			if similar, found := similarAttribute(name); found {
+				suggestion = fmt.Sprintf("did you mean %q?", similar)
+			}
+			l.report(Diagnostic{Severity: Warning, Rule: RuleUnknownAttribute, Element: element,
+				Message: fmt.Sprintf("unknown attribute %q", name), Suggestion: suggestion})
+			continue
+		}
+		kind := ""
+		for _, each := range kinds {
+			if usedBy(info, each) {
+				kind = each
+				break
+			}
+		}
+		if len(kind) == 0 {
+			l.report(Diagnostic{Severity: Warning, Rule: RuleAttributeKind, Element: element,
+				Message:    fmt.Sprintf("attribute %q is not used by %s", name, strings.Join(kinds, " or ")),
+				Suggestion: fmt.Sprintf("set it on %s instead", describeKinds(info.UsedBy))})
+			continue
+		}
+		err := dot.ValidateAttributeIn(kind, name, value, colorscheme)
+		if err == nil {
+			continue
+		}
+		suggestion := fmt.Sprintf("use a valid %s value", info.Type)
+		if values := info.Values(); len(values) > 0 {
+			suggestion = fmt.Sprintf("use one of %s", strings.Join(values, ", "))
+		}
+		l.report(Diagnostic{Severity: Error, Rule: RuleInvalidValue, Element: element,
+			Message:    fmt.Sprintf("invalid value %v for attribute %q: %s", value, name, err.(*dot.AttributeError).Message),
+			Suggestion: suggestion})
+	}
+}
+
+// colorScheme returns the colorscheme of the attributes ; empty if not set.
+func colorScheme(attributes map[string]interface{}) string {
+	if scheme, ok := attributes["colorscheme"]; ok {
+		return fmt.Sprint(scheme)
+	}
+	return ""
+}
+
+// inheritedColorScheme returns the first colorscheme set in the attributes of the (sub)graph or its parents,
+// trying each of the functions per (sub)graph ; empty if not set.
+func inheritedColorScheme(g *dot.Graph, attributesOf ...func(*dot.Graph) dot.AttributesMap) string {
+	for each := g; each != nil; each = each.Parent() {
+		for _, f := range attributesOf {
+			if scheme := f(each).Value("colorscheme"); scheme != nil {
+				return fmt.Sprint(scheme)
+			}
+		}
+	}
+	return ""
+}
+
+// lintCluster checks that a subgraph with cluster-only attributes is a cluster.
+func (l *linter) lintCluster(g *dot.Graph) {
+	if g.Parent() == nil || strings.HasPrefix(g.ID(), "cluster") {
+		return
+	}
+	styled := []string{}
+	for _, name := range sortedKeys(g.AttributesMap.Attributes()) {
+		// label is set by Subgraph for every subgraph
+		if name == "label" {
+			continue
+		}
+		if info, ok := dot.LookupAttribute(name); ok && strings.Contains(info.UsedBy, "C") && !strings.Contains(info.UsedBy, "S") {
+			styled = append(styled, name)
+		}
+	}
+	if len(styled) == 0 {
+		return
+	}
+	l.report(Diagnostic{Severity: Warning, Rule: RuleClusterPrefix, Element: describeGraph(g),
+		Message:    fmt.Sprintf("attributes %s only apply to clusters but the id %q has no cluster prefix", strings.Join(styled, ", "), g.ID()),
+		Suggestion: fmt.Sprintf("create it with dot.ClusterOption{} or use the id %q", "cluster_"+g.ID())})
+}
+
+// lintRanks checks that the nodes of the rank groups of a (sub)graph belong to it.
+func (l *linter) lintRanks(g *dot.Graph) {
+	ranks := g.Ranks()
+	for _, rank := range sortedKeys(ranks) {
+		for _, each := range ranks[rank] {
+			if g.HasNode(each) {
+				continue
+			}
+			l.report(Diagnostic{Severity: Warning, Rule: RuleForeignRank, Element: describeGraph(g),
+				Message:    fmt.Sprintf("rank=%s group has node %q of %s", rank, each.ID(), describeGraph(each.Graph())),
+				Suggestion: fmt.Sprintf("add the rank group to %s or move the node with MoveNode", describeGraph(each.Graph()))})
+		}
+	}
+}
+
+// lintEmptyLabel checks that a label, if set, is not blank.
+// The attributes are those Graphviz sees, so a label can come from a class or the defaults.
+func (l *linter) lintEmptyLabel(element string, attributes map[string]interface{}) {
+	label, ok := attributes["label"].(string)
+	if !ok || len(strings.TrimSpace(label)) > 0 {
+		return
+	}
+	l.report(Diagnostic{Severity: Info, Rule: RuleEmptyLabel, Element: element,
+		Message: "label is empty", Suggestion: "set a descriptive label or delete the label attribute"})
+}
+
+// lintDuplicateLabels checks that no two nodes have the same label, including labels set by classes.
+func (l *linter) lintDuplicateLabels(g *dot.Graph) {
+	byLabel := map[string][]dot.Node{}
+	for _, each := range sortedNodes(g.FindNodes()) {
+		if label, ok := each.EffectiveAttributes()["label"].(string); ok && len(strings.TrimSpace(label)) > 0 {
+			byLabel[label] = append(byLabel[label], each)
+		}
+	}
+	for _, label := range sortedKeys(byLabel) {
+		nodes := byLabel[label]
+		for _, each := range nodes[1:] {
+			l.report(Diagnostic{Severity: Warning, Rule: RuleDuplicateLabel, Element: describeNode(each),
+				Message:    fmt.Sprintf("label %q is also used by %s", label, describeNode(nodes[0])),
+				Suggestion: "use distinct labels ; FindNodeWithLabel only finds one of them"})
+		}
+	}
+}
+
+func (l *linter) describeEdge(e dot.Edge) string {
+	operator := "--"
+	if l.directed {
+		operator = "->"
+	}
+	return fmt.Sprintf("edge %s %s %s", describeEnd(e.From(), e.FromPort()), operator, describeEnd(e.To(), e.ToPort()))
+}
+
+func describeEnd(n dot.Node, port string) string {
+	if len(port) == 0 {
+		return fmt.Sprintf("%q", n.ID())
+	}
+	return fmt.Sprintf("%q:%s", n.ID(), port)
+}
+
+func describeNode(n dot.Node) string {
+	return fmt.Sprintf("node %q", n.ID())
+}
+
+func describeGraph(g *dot.Graph) string {
+	if g.Parent() == nil {
+		return "graph"
+	}
+	return fmt.Sprintf("subgraph %q", g.Key())
+}
+
+// describeKinds returns the element kinds for the UsedBy letters of an attribute.
+func describeKinds(usedBy string) string {
+	names := map[rune]string{'G': "graphs", 'S': "subgraphs", 'C': "clusters", 'N': "nodes", 'E': "edges"}
+	kinds := []string{}
+	for _, each := range usedBy {
+		kinds = append(kinds, names[each])
+	}
+	return strings.Join(kinds, " or ")
+}
+
+// classKinds returns the kinds of the elements of the (sub)graph, or its subgraphs, that have the class.
+// Returns all kinds if no element has it.
+func classKinds(g *dot.Graph, class string) []string {
+	found := map[string]bool{}
+	var visit func(each *dot.Graph)
+	visit = func(each *dot.Graph) {
+		if hasClass(each.AttributesMap.Attributes(), class) {
+			found["subgraph"] = true
+		}
+		for _, n := range scopeNodes(each) {
+			found["node"] = found["node"] || hasClass(n.Attributes(), class)
+		}
+		for _, e := range scopeEdges(each) {
+			found["edge"] = found["edge"] || hasClass(e.Attributes(), class)
+		}
+		for _, key := range each.SubgraphKeys() {
+			sub, _ := each.FindSubgraph(key)
+			visit(sub)
+		}
+	}
+	visit(g)
+	kinds := []string{}
+	for _, each := range []string{"node", "edge", "subgraph"} {
+		if found[each] {
+			kinds = append(kinds, each)
+		}
+	}
+	if len(kinds) == 0 {
+		return []string{"node", "edge", "subgraph"}
+	}
+	return kinds
+}
+
+func hasClass(attributes map[string]interface{}, class string) bool {
+	names, _ := attributes["class"].(string)
+	return containsString(strings.Fields(names), class)
+}
+
+// usedBy returns whether the attribute applies to the kind of element ; a subgraph can be a cluster.
+func usedBy(info dot.AttributeInfo, kind string) bool {
+	letters := map[string]string{"graph": "G", "subgraph": "SC", "node": "N", "edge": "E"}[kind]
+	return strings.ContainsAny(info.UsedBy, letters)
+}
+
+// similarAttribute returns the known attribute name closest to the (misspelled) name, if any is close enough.
+func similarAttribute(name string) (string, bool) {
+	best, bestDistance := "", 3
+	for _, each := range dot.AttributeNames() {
+		if d := editDistance(strings.ToLower(name), each); d < bestDistance {
+			best, bestDistance = each, d
+		}
+	}
+	return best, len(best) > 0
+}
+
+// editDistance returns the Levenshtein distance between two strings.
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
+
+// scopeNodes returns the nodes created in this (sub)graph, sorted by id.
+func scopeNodes(g *dot.Graph) []dot.Node {
+	nodes := []dot.Node{}
+	for _, each := range g.FindNodes() {
+		if g.HasNode(each) {
+			nodes = append(nodes, each)
+		}
+	}
+	return sortedNodes(nodes)
+}
+
+// scopeEdges returns the edges stored in this (sub)graph, by id of their From node and in order of creation.
+func scopeEdges(g *dot.Graph) []dot.Edge {
+	edgesFrom := g.EdgesMap()
+	edges := []dot.Edge{}
+	for _, id := range sortedKeys(edgesFrom) {
+		edges = append(edges, edgesFrom[id]...)
+	}
+	return edges
+}
+
+func sortedNodes(nodes []dot.Node) []dot.Node {
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID() < nodes[j].ID() })
+	return nodes
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for each := range m {
+		keys = append(keys, each)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func containsString(list []string, s string) bool {
+	for _, each := range list {
+		if each == s {
+			return true
+		}
+	}
+	return false
+}
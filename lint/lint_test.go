@@ -0,0 +1,147 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eristocrates/dot"
+)
+
+func lintStrings(diagnostics []Diagnostic) string {
+	lines := []string{}
+	for _, each := range diagnostics {
+		lines = append(lines, each.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestLintCleanGraph(t *testing.T) {
+	g := dot.NewGraph(dot.Directed)
+	g.NodeDefaults().SetAttribute("shape", "box")
+	a, b := g.Node("a"), g.Node("b").SetAttribute("fillcolor", "red")
+	g.Edge(a, b).SetAttribute("arrowhead", "vee")
+	g.Subgraph("c", dot.ClusterOption{}).SetAttribute("bgcolor", "gray")
+	if got := Lint(g); len(got) != 0 {
+		t.Errorf("got %s", lintStrings(got))
+	}
+}
+
+func TestLintAttributes(t *testing.T) {
+	g := dot.NewGraph(dot.Directed)
+	g.SetAttribute("rankdir", "sideways")
+	a := g.Node("a").SetAttribute("fillcolour", "red").SetAttribute("arrowhead", "vee")
+	g.Node("b").SetAttribute("zzzz", "1")
+	g.EdgeDefaults().SetAttribute("shape", "box")
+	g.Edge(a, a).SetAttribute("link", "-.->")
+	got := lintStrings(LintWithOptions(g, Options{Allow: []string{"link"}}))
+	want := strings.Join([]string{
+		`error: graph: invalid value sideways for attribute "rankdir": expected one of TB, LR, BT, RL (invalid-value) ; use one of TB, LR, BT, RL`,
+		`warning: edge defaults of graph: attribute "shape" is not used by edge (attribute-kind) ; set it on nodes instead`,
+		`warning: node "a": attribute "arrowhead" is not used by node (attribute-kind) ; set it on edges instead`,
+		`warning: node "a": unknown attribute "fillcolour" (unknown-attribute) ; did you mean "fillcolor"?`,
+		`warning: node "b": unknown attribute "zzzz" (unknown-attribute) ; remove it or add it to Options.Allow`,
+	}, "\n")
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestLintColorScheme(t *testing.T) {
+	g := dot.NewGraph(dot.Directed)
+	g.NodeDefaults().SetAttribute("colorscheme", "blues9")
+	g.Node("a").SetAttribute("fillcolor", "3")
+	g.Edge(g.Node("b"), g.Node("c")).SetAttribute("color", "3")
+	got := lintStrings(Lint(g))
+	want := `error: edge "b" -> "c": invalid value 3 for attribute "color": expected H,S,V (invalid-value) ; use a valid color value`
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestLintClasses(t *testing.T) {
+	g := dot.NewGraph(dot.Directed)
+	g.DefineClass("db", map[string]interface{}{"shape": "cylinder", "arrowhead": "vee", "colour": "red"})
+	g.DefineClass("flow", map[string]interface{}{"arrowhead": "nope", "penwidth": "2"})
+	g.DefineClass("unused", map[string]interface{}{"arrowhead": "vee", "shape": "box"})
+	a := g.Node("a").Class("db")
+	a.Edge(a).Class("flow")
+	got := lintStrings(Lint(g))
+	want := strings.Join([]string{
+		`warning: class "db" of graph: attribute "arrowhead" is not used by node (attribute-kind) ; set it on edges instead`,
+		`warning: class "db" of graph: unknown attribute "colour" (unknown-attribute) ; did you mean "color"?`,
+		`error: class "flow" of graph: invalid value nope for attribute "arrowhead": unknown arrow type (invalid-value) ; use one of normal, inv, dot, invdot, odot, invodot, none, tee, empty, invempty, diamond, odiamond, ediamond, crow, box, obox, open, halfopen, vee`,
+	}, "\n")
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestLintClusterPrefix(t *testing.T) {
+	g := dot.NewGraph(dot.Directed)
+	g.Subgraph("plain").SetAttribute("rank", "same")
+	styled := g.Subgraph("styled")
+	styled.SetAttribute("bgcolor", "gray")
+	styled.SetAttribute("pencolor", "red")
+	got := lintStrings(Lint(g))
+	want := `warning: subgraph "styled": attributes bgcolor, pencolor only apply to clusters but the id "s2" has no cluster prefix (cluster-prefix) ; create it with dot.ClusterOption{} or use the id "cluster_s2"`
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestLintForeignRank(t *testing.T) {
+	g := dot.NewGraph(dot.Directed)
+	a := g.Node("a")
+	sub := g.Subgraph("sub")
+	b := sub.Node("b")
+	sub.AddToSameRank(a, b)
+	got := lintStrings(Lint(g))
+	want := `warning: subgraph "sub": rank=same group has node "a" of graph (foreign-rank) ; add the rank group to graph or move the node with MoveNode`
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestLintLabels(t *testing.T) {
+	g := dot.NewGraph(dot.Undirected)
+	a := g.Node("a").Label(" ")
+	b := g.Node("b").Label("same")
+	g.Subgraph("sub").Node("c").Label("same")
+	g.Edge(a, b, "")
+	got := lintStrings(Lint(g))
+	want := strings.Join([]string{
+		`info: node "a": label is empty (empty-label) ; set a descriptive label or delete the label attribute`,
+		`info: edge "a" -- "b": label is empty (empty-label) ; set a descriptive label or delete the label attribute`,
+		`warning: node "c": label "same" is also used by node "b" (duplicate-label) ; use distinct labels ; FindNodeWithLabel only finds one of them`,
+	}, "\n")
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestLintLabelsOfClasses(t *testing.T) {
+	g := dot.NewGraph(dot.Directed)
+	g.DefineClass("quiet", map[string]interface{}{"label": " "})
+	g.DefineClass("title", map[string]interface{}{"label": "same"})
+	a := g.Node("a").Label("same")
+	b := g.Node("b").Class("title")
+	b.Delete("label")
+	a.Edge(b).Class("quiet")
+	got := lintStrings(Lint(g))
+	want := strings.Join([]string{
+		`info: edge "a" -> "b": label is empty (empty-label) ; set a descriptive label or delete the label attribute`,
+		`warning: node "b": label "same" is also used by node "a" (duplicate-label) ; use distinct labels ; FindNodeWithLabel only finds one of them`,
+	}, "\n")
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestLintOptions(t *testing.T) {
+	g := dot.NewGraph(dot.Directed)
+	g.Node("a").Label("").SetAttribute("colour", "red")
+	got := lintStrings(LintWithOptions(g, Options{MinSeverity: Warning, Ignore: []string{RuleUnknownAttribute}}))
+	if got != "" {
+		t.Errorf("got %s", got)
+	}
+}
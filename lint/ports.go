@@ -0,0 +1,30 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/eristocrates/dot"
+)
+
+// lintPort checks that the port of an edge end exists in the record or HTML-like label of its node.
+func (l *linter) lintPort(e dot.Edge, n dot.Node, port string) {
+	name := dot.Port(port).Name()
+	if len(name) == 0 {
+		return
+	}
+	ports, hasPorts := n.Ports()
+	if containsString(ports, name) {
+		return
+	}
+	suggestion := "use a record shape or HTML-like label with this port, or a compass point such as \"ne\""
+	if hasPorts {
+		suggestion = fmt.Sprintf("use one of the ports %s", strings.Join(ports, ", "))
+		if len(ports) == 0 {
+			suggestion = "name a field of the label with <port> (record) or PORT=\"port\" (HTML-like)"
+		}
+	}
+	l.report(Diagnostic{Severity: Error, Rule: RuleUnknownPort, Element: l.describeEdge(e),
+		Message:    fmt.Sprintf("node %q has no port %q", n.ID(), name),
+		Suggestion: suggestion})
+}
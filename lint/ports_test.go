@@ -0,0 +1,27 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eristocrates/dot"
+)
+
+func TestLintPorts(t *testing.T) {
+	g := dot.NewGraph(dot.Directed)
+	record := g.Node("record").SetAttribute("shape", "record").Label(`<in> in|{<a> a|<b\>> b}|\<c\>|<out> out`)
+	html := g.Node("html").SetAttribute("label", dot.HTML(`<table><tr><td PORT="p1">1</td><td port='p2'>2</td></tr></table>`))
+	plain := g.Node("plain")
+	g.EdgeWithPorts(record, html, "out:se", "p2")
+	g.EdgeWithPorts(record, html, "x", "p3")
+	g.EdgeWithPorts(plain, plain, "n", "left")
+	got := lintStrings(Lint(g))
+	want := strings.Join([]string{
+		`error: edge "plain":n -> "plain":left: node "plain" has no port "left" (unknown-port) ; use a record shape or HTML-like label with this port, or a compass point such as "ne"`,
+		`error: edge "record":x -> "html":p3: node "record" has no port "x" (unknown-port) ; use one of the ports in, a, b>, out`,
+		`error: edge "record":x -> "html":p3: node "html" has no port "p3" (unknown-port) ; use one of the ports p1, p2`,
+	}, "\n")
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,202 @@
+package dot
+
+import (
+	"sort"
+	"strings"
+)
+
+// MergeConflict tells Merge what to do with a node of the other graph whose id is already in use.
+type MergeConflict int
+
+const (
+	// MergeKeep uses the existing node and ignores the attributes of the other node.
+	MergeKeep MergeConflict = iota
+	// MergeOverwrite uses the existing node and replaces its attributes with those of the other node.
+	MergeOverwrite
+	// MergeAttributes uses the existing node and sets the attributes of the other node,
+	// keeping attributes that only the existing node has.
+	MergeAttributes
+	// MergePrefix imports the other node with MergeOptions.Prefix put before its id, until it is unique.
+	MergePrefix
+)
+
+// MergeOptions are the options for Graph.Merge.
+type MergeOptions struct {
+	// Conflict is the policy for nodes of the other graph with an id that is already in use.
+	Conflict MergeConflict
+	// Prefix is used with MergePrefix ; "merged_" if empty.
+	Prefix string
+	// Cluster, if not empty, is the id of a cluster subgraph in which the other graph is imported.
+	// The attributes of the other graph are set on that cluster.
+	Cluster string
+}
+
+// Merge imports the nodes, edges, subgraphs and rank groups of the other graph into this (sub)graph.
+// Subgraphs are matched by the id with which they were created. Nodes are matched by id anywhere in the root graph ;
+// see MergeConflict for how existing nodes are handled. Imported nodes and subgraphs get new sequence numbers.
+// Graph attributes, classes and node and edge defaults of the other graph are only set if absent,
+// unless a Cluster is used for the graph attributes. Attributes are set as with SetAttribute,
+// so that they are checked if the root graph has the StrictAttributesOption.
+// Rank groups only get the nodes of their own (sub)graph ; an existing node of another (sub)graph is left out.
+// The other graph is not changed.
+func (g *Graph) Merge(other *Graph, options MergeOptions) {
+	if len(options.Prefix) == 0 {
+		options.Prefix = "merged_"
+	}
+	target := g
+	if len(options.Cluster) > 0 {
+		target = g.Subgraph(options.Cluster, ClusterOption{})
+		for k, v := range other.attributes {
+			target.AttributesMap.SetAttribute(k, v)
+		}
+	} else {
+		for k, v := range other.attributes {
+			if _, ok := g.attributes[k]; !ok {
+				g.AttributesMap.SetAttribute(k, v)
+			}
+		}
+	}
+	importClassesAndDefaults(other, target)
+	m := &merger{root: g.Root(), options: options, nodes: map[int]Node{}, scopes: map[*Graph]*Graph{}}
+	m.importScope(other, target)
+	for from, into := range m.scopes {
+		m.importRanks(from, into)
+	}
+	m.importEdges(other)
+}
+
+type merger struct {
+	root    *Graph
+	options MergeOptions
+	// nodes has the imported (or existing) node by seq of the node of the other graph
+	nodes map[int]Node
+	// sources has the nodes of the other graph
+	sources []Node
+	// scopes has the (sub)graph of this graph by (sub)graph of the other
+	scopes map[*Graph]*Graph
+}
+
+// importClassesAndDefaults sets the classes and node, edge and graph defaults that are absent.
+func importClassesAndDefaults(from, into *Graph) {
+	for name := range from.classes {
+		if _, ok := into.classes[name]; !ok {
+			attributes, _ := from.ClassAttributes(name)
+			into.DefineClass(name, attributes)
+		}
+	}
+	importAbsent(from.nodeDefaults, into.NodeDefaults())
+	importAbsent(from.edgeDefaults, into.EdgeDefaults())
+	importAbsent(from.graphDefaults, into.GraphDefaults())
+}
+
+// importAbsent sets the attributes that the map does not have yet.
+func importAbsent(attributes map[string]interface{}, into AttributesMap) {
+	for k, v := range attributes {
+		if _, ok := into.attributes[k]; !ok {
+			into.SetAttribute(k, v)
+		}
+	}
+}
+
+// importScope imports the nodes and subgraphs of a (sub)graph of the other graph, recursively.
+func (m *merger) importScope(from, into *Graph) {
+	m.scopes[from] = into
+	for _, key := range from.sortedNodesKeys() {
+		m.importNode(from.nodes[key], into)
+	}
+	for _, key := range from.sortedSubgraphsKeys() {
+		sub := from.subgraphs[key]
+		options := []GraphOption{}
+		if strings.HasPrefix(sub.id, "cluster") {
+			options = append(options, ClusterOption{})
+		}
+		imported := into.Subgraph(key, options...)
+		for k, v := range sub.attributes {
+			imported.AttributesMap.SetAttribute(k, v)
+		}
+		importClassesAndDefaults(sub, imported)
+		m.importScope(sub, imported)
+	}
+}
+
+func (m *merger) importNode(n Node, into *Graph) {
+	m.sources = append(m.sources, n)
+	id := n.id
+	existing, exists := m.root.FindNodeById(id)
+	if exists {
+		switch m.options.Conflict {
+		case MergeKeep:
+			m.nodes[n.seq] = existing
+			return
+		case MergeOverwrite:
+			for k := range existing.attributes {
+				delete(existing.attributes, k)
+			}
+			fallthrough
+		case MergeAttributes:
+			for k, v := range n.attributes {
+				existing.SetAttribute(k, v)
+			}
+			m.nodes[n.seq] = existing
+			return
+		}
+		for exists {
+			id = m.options.Prefix + id
+			_, exists = m.root.FindNodeById(id)
+		}
+	}
+	imported := into.Node(id)
+	for k := range imported.attributes {
+		if _, ok := n.attributes[k]; !ok {
+			delete(imported.attributes, k)
+		}
+	}
+	for k, v := range n.attributes {
+		imported.SetAttribute(k, v)
+	}
+	m.nodes[n.seq] = imported
+}
+
+// importRanks adds the rank groups of a (sub)graph of the other graph to the matching (sub)graph.
+// Nodes that are not in that (sub)graph, such as existing nodes kept for a conflict, are left out.
+func (m *merger) importRanks(from, into *Graph) {
+	intoGroups := into.rankGroups()
+	for i, groups := range from.rankGroups() {
+		for key, nodes := range groups {
+			for _, n := range nodes {
+				member := m.nodes[n.seq]
+				if into.HasNode(member) && !containsNode(intoGroups[i][key], member) {
+					intoGroups[i][key] = append(intoGroups[i][key], member)
+				}
+			}
+		}
+	}
+}
+
+// containsNode returns whether the list has the node, by sequence number.
+func containsNode(nodes []Node, n Node) bool {
+	for _, each := range nodes {
+		if each.seq == n.seq {
+			return true
+		}
+	}
+	return false
+}
+
+// importEdges creates the edges of the other graph, per node sorted by id, in order of creation.
+func (m *merger) importEdges(other *Graph) {
+	root := other.Root()
+	sort.SliceStable(m.sources, func(i, j int) bool { return m.sources[i].id < m.sources[j].id })
+	for _, each := range m.sources {
+		for _, e := range root.outIndex[each.seq] {
+			to, ok := m.nodes[e.to.seq]
+			if !ok {
+				continue
+			}
+			imported := m.root.EdgeWithPorts(m.nodes[each.seq], to, e.fromPort, e.toPort)
+			for k, v := range e.attributes {
+				imported.SetAttribute(k, v)
+			}
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package dot
+
+import (
+	"strings"
+	"testing"
+)
+
+func mergeSource() *Graph {
+	other := NewGraph(Directed)
+	other.SetAttribute("rankdir", "LR")
+	sub := other.Subgraph("api", ClusterOption{})
+	a := other.Node("a").SetAttribute("color", "blue")
+	b := sub.Node("b")
+	a.Edge(b, "calls")
+	other.AddToSameRank(a)
+	return other
+}
+
+func TestMergeKeep(t *testing.T) {
+	di := NewGraph(Directed)
+	di.Node("a").SetAttribute("shape", "box")
+	di.Merge(mergeSource(), MergeOptions{})
+	if got, want := flatten(di.String()), `digraph  {subgraph cluster_s2 {label="api";n3[label="b"];}rankdir="LR";n1[label="a",shape="box"];n1->n3[label="calls"];{rank=same; n1;};}`; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestMergeAttributesAndOverwrite(t *testing.T) {
+	di := NewGraph(Directed)
+	a := di.Node("a").SetAttribute("shape", "box")
+	di.Merge(mergeSource(), MergeOptions{Conflict: MergeAttributes})
+	if got, want := a.Value("shape").(string)+" "+a.Value("color").(string), "box blue"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	di.Merge(mergeSource(), MergeOptions{Conflict: MergeOverwrite})
+	if got, want := a.Value("shape"), interface{}(nil); got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	// b is imported once, the edge twice
+	if got, want := len(di.FindNodes()), 2; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := di.OutDegree(a), 2; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestMergePrefixInCluster(t *testing.T) {
+	di := NewGraph(Directed, NodeIDOption{})
+	di.Node("a")
+	di.Merge(mergeSource(), MergeOptions{Conflict: MergePrefix, Prefix: "x_", Cluster: "service"})
+	if got, want := flatten(di.String()), `digraph  {subgraph cluster_s2 {subgraph cluster_s4 {label="api";b[label="b"];}label="service";rankdir="LR";x_a[color="blue",label="a"];x_a->b[label="calls"];{rank=same; x_a;};}a[label="a"];}`; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestMergeStrictAttributes(t *testing.T) {
+	di := NewGraph(Directed, StrictAttributesOption{})
+	other := NewGraph(Directed)
+	other.NodeDefaults().SetAttribute("shape", "rectange")
+	other.Node("a").SetAttribute("fillcolour", "red").Edge(other.Node("b")).SetAttribute("penwidth", "thick")
+	di.Merge(other, MergeOptions{Cluster: "other"})
+	got := []string{}
+	for _, each := range di.AttributeErrors() {
+		got = append(got, each.Error())
+	}
+	want := []string{
+		"node attribute shape=rectange: expected one of " + strings.Join(attributeEnums["shape"], ", "),
+		"node attribute fillcolour=red: unknown attribute",
+		"edge attribute penwidth=thick: expected a number",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestMergeKeepRanksOfOtherScope(t *testing.T) {
+	di := NewGraph(Directed)
+	sub := di.Subgraph("sub")
+	a := sub.Node("a")
+	other := NewGraph(Directed)
+	other.AddToSameRank(other.Node("a"), other.Node("c"))
+	di.Merge(other, MergeOptions{})
+	if got, want := nodeIDs(di.Ranks()["same"]), "c"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if len(sub.Ranks()) != 0 || !sub.HasNode(a) {
+		t.Errorf("unexpected ranks %v of sub", sub.Ranks())
+	}
+}
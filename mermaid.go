@@ -3,7 +3,9 @@ package dot
 import (
 	"fmt"
 	"html"
+	"regexp"
 	"strings"
+	"unicode"
 )
 
 const (
@@ -15,43 +17,64 @@ const (
 )
 
 var (
-	MermaidShapeRound            = shape{"(", ")"}
-	MermaidShapeStadium          = shape{"([", "])"}
-	MermaidShapeSubroutine       = shape{"[[", "]]"}
-	MermaidShapeCylinder         = shape{"[(", ")]"}
-	MermaidShapeCirle            = shape{"((", "))"} // Deprecated: use MermaidShapeCircle instead
-	MermaidShapeCircle           = shape{"((", "))"}
-	MermaidShapeAsymmetric       = shape{">", "]"}
-	MermaidShapeRhombus          = shape{"{", "}"}
-	MermaidShapeTrapezoid        = shape{"[/", "\\]"}
-	MermaidShapeTrapezoidAlt     = shape{"[\\", "/]"}
-	MermaidShapeHexagon          = shape{"{{", "}}"}
-	MermaidShapeParallelogram    = shape{"[/", "/]"}
-	MermaidShapeParallelogramAlt = shape{"[\\", "\\]"}
-	// TODO more shapes see https://mermaid.js.org/syntax/flowchart.html#node-shapes
+	MermaidShapeRound            = shape{"(", ")", "rounded"}
+	MermaidShapeStadium          = shape{"([", "])", "stadium"}
+	MermaidShapeSubroutine       = shape{"[[", "]]", "fr-rect"}
+	MermaidShapeCylinder         = shape{"[(", ")]", "cyl"}
+	MermaidShapeCirle            = shape{"((", "))", "circle"} // Deprecated: use MermaidShapeCircle instead
+	MermaidShapeCircle           = shape{"((", "))", "circle"}
+	MermaidShapeAsymmetric       = shape{">", "]", "odd"}
+	MermaidShapeRhombus          = shape{"{", "}", "diam"}
+	MermaidShapeTrapezoid        = shape{"[/", "\\]", "trap-b"}
+	MermaidShapeTrapezoidAlt     = shape{"[\\", "/]", "trap-t"}
+	MermaidShapeHexagon          = shape{"{{", "}}", "hex"}
+	MermaidShapeParallelogram    = shape{"[/", "/]", "lean-r"}
+	MermaidShapeParallelogramAlt = shape{"[\\", "\\]", "lean-l"}
+	// for more shapes see mermaid_shapes.go
 )
 
+// shape is a Mermaid node shape.
+// The open and close brackets are used in the classic syntax, the name in the extended (@{ shape: name }) syntax.
 type shape struct {
 	open, close string
+	name        string
+}
+
+// MermaidOptions controls the output of MermaidFlowchartWithOptions.
+type MermaidOptions struct {
+	// Orientation is one of the Mermaid{TopToBottom,TopDown,BottomToTop,RightToLeft,LeftToRight} constants.
+	Orientation int
+	// ExtendedShapes writes nodes using the @{ shape: name, label: "text" } syntax.
+	ExtendedShapes bool
+	// Interactive writes click directives for nodes with a "href" (or "URL") attribute ;
+	// the "tooltip" and "target" attributes are used too.
+	Interactive bool
+	// MarkdownLabels writes node, edge and subgraph labels as Markdown strings.
+	MarkdownLabels bool
 }
 
 func MermaidGraph(g *Graph, orientation int) string {
-	return diagram(g, "graph", orientation)
+	return diagram(g, "graph", MermaidOptions{Orientation: orientation})
 }
 
 func MermaidFlowchart(g *Graph, orientation int) string {
-	return diagram(g, "flowchart", orientation)
+	return diagram(g, "flowchart", MermaidOptions{Orientation: orientation})
+}
+
+// MermaidFlowchartWithOptions returns the graph as a Mermaid flowchart using the options.
+func MermaidFlowchartWithOptions(g *Graph, options MermaidOptions) string {
+	return diagram(g, "flowchart", options)
 }
 
 func escape(value string) string {
 	return fmt.Sprintf(`"%s"`, html.EscapeString(value))
 }
 
-func diagram(g *Graph, diagramType string, orientation int) string {
+func diagram(g *Graph, diagramType string, options MermaidOptions) string {
 	sb := new(strings.Builder)
 	sb.WriteString(diagramType)
 	sb.WriteRune(' ')
-	switch orientation {
+	switch options.Orientation {
 	case MermaidTopDown, MermaidTopToBottom:
 		sb.WriteString("TD")
 	case MermaidBottomToTop:
@@ -64,22 +87,167 @@ func diagram(g *Graph, diagramType string, orientation int) string {
 		sb.WriteString("TD")
 	}
 	writeEnd(sb)
-	diagramGraph(g, sb)
-	for _, id := range g.sortedSubgraphsKeys() {
-		each := g.subgraphs[id]
-		fmt.Fprintf(sb, "subgraph %s [%s];\n", id, each.attributes["label"])
-		diagramGraph(each, sb)
-		fmt.Fprintln(sb, "end;")
+	w := &mermaidWriter{
+		sb:           sb,
+		subgraphIDs:  map[*Graph]string{},
+		usedIDs:      map[string]bool{},
+		edgesOfScope: map[*Graph][]Edge{},
+		styles:       newMermaidStyles(),
+		directed:     g.Root().IsDirected(),
+		options:      options,
 	}
+	w.collect(g)
+	w.writeScope(g, 1)
+	w.styles.write(sb)
 	return sb.String()
 }
 
-func diagramGraph(g *Graph, sb *strings.Builder) {
-	// graph nodes
+// mermaidWriter holds the state for writing a graph and its nested subgraphs.
+type mermaidWriter struct {
+	sb          *strings.Builder
+	subgraphIDs map[*Graph]string
+	usedIDs     map[string]bool
+	// edgesOfScope has the edges by the (sub)graph that encloses both nodes.
+	edgesOfScope map[*Graph][]Edge
+	styles       *mermaidStyles
+	directed     bool
+	options      MermaidOptions
+}
+
+// text returns the quoted (and escaped) text, as a Markdown string if requested.
+// The Markdown of such a label is kept ; only the characters that end the string are replaced.
+func (w *mermaidWriter) text(value string) string {
+	if w.options.MarkdownLabels {
+		return fmt.Sprintf("\"`%s`\"", markdownString.Replace(value))
+	}
+	return escape(value)
+}
+
+// lines returns the quoted (and escaped) lines of text, separated by <br> or by newlines in a Markdown string.
+// The lines are plain text so in a Markdown string their Markdown characters are escaped.
+func (w *mermaidWriter) lines(lines []string) string {
+	escaped := make([]string, len(lines))
+	for i, each := range lines {
+		if w.options.MarkdownLabels {
+			escaped[i] = markdownString.Replace(markdownText.Replace(each))
+		} else {
+			escaped[i] = html.EscapeString(each)
+		}
+	}
+	if w.options.MarkdownLabels {
+		return fmt.Sprintf("\"`%s`\"", strings.Join(escaped, "\n"))
+	}
+	return fmt.Sprintf(`"%s"`, strings.Join(escaped, "<br>"))
+}
+
+var (
+	// markdownString replaces the quote and backtick that would end a Markdown string by entity codes.
+	markdownString = strings.NewReplacer(`"`, "#quot;", "`", "#96;")
+	// markdownText escapes the characters of plain text that have a meaning in Markdown.
+	markdownText = strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "~", `\~`, "[", `\[`, "]", `\]`, "<", `\<`, ">", `\>`)
+)
+
+// recordLines returns the non-empty texts of the fields, including nested ones, split at the line breaks \n, \l and \r.
+// Other backslash sequences are the escaped character, as Graphviz reads them in a record.
+func recordLines(fields []RecordField) []string {
+	lines := []string{}
+	for _, each := range fields {
+		if each.IsNested() {
+			lines = append(lines, recordLines(each.Fields)...)
+			continue
+		}
+		text := recordEscape.ReplaceAllStringFunc(each.Text, func(escape string) string {
+			if strings.ContainsAny(escape[1:], "nlr") {
+				return "\n"
+			}
+			return escape[1:]
+		})
+		for _, line := range strings.Split(text, "\n") {
+			if line = strings.TrimSpace(line); len(line) > 0 {
+				lines = append(lines, line)
+			}
+		}
+	}
+	return lines
+}
+
+var recordEscape = regexp.MustCompile(`\\.`)
+
+// collect assigns the subgraph identifiers and the scope of each edge, in sorted order.
+func (w *mermaidWriter) collect(g *Graph) {
+	for _, key := range g.sortedEdgesFromKeys() {
+		for _, each := range g.edgesFrom[key] {
+			scope := commonParentOf(each.from.graph, each.to.graph)
+			w.edgesOfScope[scope] = append(w.edgesOfScope[scope], each)
+		}
+	}
+	for _, key := range g.sortedSubgraphsKeys() {
+		sub := g.subgraphs[key]
+		w.subgraphIDs[sub] = w.uniqueID(key)
+		w.collect(sub)
+	}
+}
+
+var mermaidKeywords = map[string]bool{
+	"end": true, "graph": true, "flowchart": true, "subgraph": true, "direction": true,
+	"style": true, "class": true, "classDef": true, "linkStyle": true, "click": true, "call": true,
+}
+
+// uniqueID returns a valid Mermaid identifier for a subgraph key that is not used before.
+// Identifiers that could clash with the generated node identifiers (n<seq>) get a suffix too.
+func (w *mermaidWriter) uniqueID(key string) string {
+	id := strings.Map(func(r rune) rune {
+		if r == '_' || r == '-' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, key)
+	if len(id) == 0 || mermaidKeywords[id] || mermaidNodeIDPattern.MatchString(id) {
+		id = "sub_" + id
+	}
+	unique := id
+	for i := 2; w.usedIDs[unique]; i++ {
+		unique = fmt.Sprintf("%s_%d", id, i)
+	}
+	w.usedIDs[unique] = true
+	return unique
+}
+
+var mermaidNodeIDPattern = regexp.MustCompile(`^n[0-9]+$`)
+
+// writeScope writes the nodes, the nested subgraphs and then the edges of a (sub)graph.
+func (w *mermaidWriter) writeScope(g *Graph, depth int) {
+	w.writeNodes(g, depth)
+	indent := strings.Repeat("\t", depth)
+	for _, key := range g.sortedSubgraphsKeys() {
+		each := g.subgraphs[key]
+		fmt.Fprintf(w.sb, "%ssubgraph %s", indent, w.subgraphIDs[each])
+		if label := each.Value("label"); label != nil {
+			fmt.Fprintf(w.sb, " [%s]", w.text(fmt.Sprintf("%v", label)))
+		}
+		writeEnd(w.sb)
+		if direction := each.Value("direction"); direction != nil {
+			fmt.Fprintf(w.sb, "%s\tdirection %v", indent, direction)
+			writeEnd(w.sb)
+		}
+		w.styles.addClasses(each, each.attributes, w.subgraphIDs[each])
+		w.writeScope(each, depth+1)
+		fmt.Fprintf(w.sb, "%send", indent)
+		writeEnd(w.sb)
+	}
+	w.writeEdges(w.edgesOfScope[g], depth)
+}
+
+func (w *mermaidWriter) writeNodes(g *Graph, depth int) {
+	sb := w.sb
+	indent := strings.Repeat("\t", depth)
 	for _, key := range g.sortedNodesKeys() {
 		nodeShape := MermaidShapeRound
 		each := g.nodes[key]
-		if s := each.Attribute("shape"); s != nil {
+		// shape, label and link can come from a class or the defaults ; the CSS of classes is written as classDef
+		resolved := each
+		resolved.AttributesMap = AttributesMap{attributes: g.effectiveAttributes(each.attributes, nodeDefaultsOf)}
+		if s := resolved.Attribute("shape"); s != nil {
 			// could be a shape or a string
 			shapeString, ok := s.(string)
 			if ok {
@@ -96,76 +264,140 @@ func diagramGraph(g *Graph, sb *strings.Builder) {
 			}
 		}
 		txt := "?"
-		if label := each.Attribute("label"); label != nil {
-			// take string only
-			slabel, ok := label.(string)
-			if ok {
+		if label := resolved.Attribute("label"); label != nil {
+			// take string (or quoted Literal) only
+			if slabel, ok := labelText(label); ok {
 				txt = slabel
 			}
 		}
-		fmt.Fprintf(sb, "\tn%d%s%s%s;\n", each.seq, nodeShape.open, escape(txt), nodeShape.close)
-		if style := each.Attribute("style"); style != nil {
-			fmt.Fprintf(sb, "\tstyle n%d %s\n", each.seq, style.(string))
-		}
-	}
-	// all edges
-	// graph edges
-	denoteEdge := "-->"
-	if g.graphType == "graph" {
-		denoteEdge = "---"
-	}
-	for _, each := range g.sortedEdgesFromKeys() {
-		all := g.edgesFrom[each]
-		for _, each := range all {
-			// The edge can override the link style
-			link := denoteEdge
-			if l := each.Attribute("link"); l != nil {
-				// take string only
-				slink, ok := l.(string)
-				if ok {
-					link = slink
+		label := w.text(txt)
+		// Mermaid has no records ; their fields are written as lines
+		if fields, err := resolved.RecordFields(); err == nil {
+			label = w.lines(recordLines(fields))
+		}
+		// an invisible node keeps its place in the layout but has no label, see nodeCSS
+		invisible := hasStyle(resolved.AttributesMap, "invis")
+		if invisible {
+			label = w.text(" ")
+		}
+		if w.options.ExtendedShapes && len(nodeShape.name) > 0 {
+			fmt.Fprintf(sb, "%sn%d@{ shape: %s, label: %s };\n", indent, each.seq, nodeShape.name, label)
+		} else {
+			fmt.Fprintf(sb, "%sn%d%s%s%s;\n", indent, each.seq, nodeShape.open, label, nodeShape.close)
+		}
+		if w.options.Interactive && !invisible {
+			w.writeClick(resolved, indent)
+		}
+		w.styles.addClasses(g, each.attributes, fmt.Sprintf("n%d", each.seq))
+		// the CSS of the node has its defaults, except those overridden by its classes, and its own attributes
+		own := g.inheritedDefaults(nodeDefaultsOf)
+		for _, name := range classNames(each.attributes) {
+			if class, ok := g.ClassAttributes(name); ok {
+				for k := range class {
+					delete(own, k)
 				}
 			}
-			if label := each.Attribute("label"); label != nil {
-				slabel, ok := label.(string)
-				if !ok {
-					// make it a string
-					slabel = fmt.Sprintf("%v", label)
-				}
-				if label != "" {
-					fmt.Fprintf(sb, "\tn%d%s|%s|n%d;\n", each.from.seq, link, escape(slabel), each.to.seq)
-					continue
-				}
+		}
+		for k, v := range each.attributes {
+			own[k] = v
+		}
+		css := nodeCSS(AttributesMap{attributes: own})
+		// a style with CSS properties is written as is
+		if style, ok := each.Attribute("style").(string); ok && strings.Contains(style, ":") {
+			if len(css) > 0 {
+				style += "," + css
 			}
-			// no label
-			fmt.Fprintf(sb, "\tn%d%sn%d;\n", each.from.seq, link, each.to.seq)
+			fmt.Fprintf(sb, "%sstyle n%d %s", indent, each.seq, style)
+			writeEnd(sb)
+			continue
+		}
+		if len(css) > 0 {
+			w.styles.addNode(fmt.Sprintf("n%d", each.seq), css)
 		}
 	}
 }
 
+// writeClick writes a click directive if the node has a link.
+func (w *mermaidWriter) writeClick(n Node, indent string) {
+	href := stringAttribute(n.AttributesMap, "href")
+	if len(href) == 0 {
+		href = stringAttribute(n.AttributesMap, "URL")
+	}
+	if len(href) == 0 {
+		return
+	}
+	fmt.Fprintf(w.sb, "%sclick n%d href %s", indent, n.seq, mermaidString(href))
+	if tooltip := stringAttribute(n.AttributesMap, "tooltip"); len(tooltip) > 0 {
+		fmt.Fprintf(w.sb, " %s", mermaidString(tooltip))
+	}
+	if target := stringAttribute(n.AttributesMap, "target"); len(target) > 0 {
+		fmt.Fprintf(w.sb, " %s", target)
+	}
+	writeEnd(w.sb)
+}
+
+// mermaidString quotes a value ; Mermaid strings cannot have escaped quotes.
+func mermaidString(value string) string {
+	return `"` + strings.ReplaceAll(value, `"`, "#quot;") + `"`
+}
+
+func (w *mermaidWriter) writeEdges(edges []Edge, depth int) {
+	sb := w.sb
+	indent := strings.Repeat("\t", depth)
+	for _, each := range edges {
+		// links cannot have a class, so their CSS includes that of the classes and the defaults
+		each.AttributesMap = AttributesMap{attributes: each.graph.effectiveAttributes(each.attributes, edgeDefaultsOf)}
+		w.styles.addLink(edgeCSS(each.AttributesMap))
+		// The edge can override the link style
+		link := mermaidLinkOf(each.AttributesMap, w.directed)
+		if l := each.Attribute("link"); l != nil {
+			// take string only
+			slink, ok := l.(string)
+			if ok {
+				link = slink
+			}
+		}
+		if label := each.Attribute("label"); label != nil {
+			slabel, ok := label.(string)
+			if !ok {
+				// make it a string
+				slabel = fmt.Sprintf("%v", label)
+			}
+			if label != "" {
+				fmt.Fprintf(sb, "%sn%d%s|%s|n%d;\n", indent, each.from.seq, link, w.text(slabel), each.to.seq)
+				continue
+			}
+		}
+		// no label
+		fmt.Fprintf(sb, "%sn%d%sn%d;\n", indent, each.from.seq, link, each.to.seq)
+	}
+}
+
 func writeEnd(sb *strings.Builder) {
 	sb.WriteString(";\n")
 }
 
 func lookupShape(shapeName string) (shape, bool) {
 	switch shapeName {
-	case "round", "box":
+	case "round", "box", "Mrecord", "mrecord":
 		return MermaidShapeRound, true
-	case "asymmetric":
+	case "record":
+		return MermaidShapeRect, true
+	case "asymmetric", "cds":
 		return MermaidShapeAsymmetric, true
 	case "circle":
 		return MermaidShapeCircle, true
 	case "cylinder":
 		return MermaidShapeCylinder, true
-	case "rhombux":
+	case "rhombus", "rhombux", "diamond":
 		return MermaidShapeRhombus, true
 	case "stadium":
 		return MermaidShapeStadium, true
 	case "subroutine":
 		return MermaidShapeSubroutine, true
-	case "trapezoid":
+	case "trapezoid", "trapezium":
 		return MermaidShapeTrapezoid, true
-	case "trapezoid-alt":
+	case "trapezoid-alt", "invtrapezium":
 		return MermaidShapeTrapezoidAlt, true
 	case "hexagon":
 		return MermaidShapeHexagon, true
@@ -173,6 +405,12 @@ func lookupShape(shapeName string) (shape, bool) {
 		return MermaidShapeParallelogram, true
 	case "parallelogram-alt":
 		return MermaidShapeParallelogramAlt, true
+	case "doublecircle":
+		return MermaidShapeDoubleCircle, true
+	}
+	// names and aliases of the extended syntax
+	if each, ok := mermaidShapeCatalogue[shapeName]; ok {
+		return each, true
 	}
 	return shape{}, false
 }
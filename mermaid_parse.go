@@ -0,0 +1,638 @@
+package dot
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// mermaidNodeShapes lists the node shape delimiters of Mermaid flowcharts, longest opening first.
+// Each is mapped onto Graphviz attributes ; see also lookupShape for the reverse mapping.
+var mermaidNodeShapes = []struct {
+	open, close string
+	attributes  []string
+}{
+	{"(((", ")))", []string{"shape", "doublecircle"}},
+	{"([", "])", []string{"shape", "box", "style", "rounded"}},
+	{"[[", "]]", []string{"shape", "box", "peripheries", "2"}},
+	{"[(", ")]", []string{"shape", "cylinder"}},
+	{"((", "))", []string{"shape", "circle"}},
+	{"{{", "}}", []string{"shape", "hexagon"}},
+	{"[/", "/]", []string{"shape", "parallelogram"}},
+	{"[/", "\\]", []string{"shape", "trapezium"}},
+	{"[\\", "\\]", []string{"shape", "parallelogram"}},
+	{"[\\", "/]", []string{"shape", "invtrapezium"}},
+	{">", "]", []string{"shape", "cds"}},
+	{"(", ")", []string{"shape", "box", "style", "rounded"}},
+	{"[", "]", []string{"shape", "box"}},
+	{"{", "}", []string{"shape", "diamond"}},
+}
+
+var (
+	mermaidHeader     = regexp.MustCompile(`^(flowchart|graph)(\s+(TB|TD|BT|RL|LR))?$`)
+	mermaidDirection  = regexp.MustCompile(`^direction\s+(TB|TD|BT|RL|LR)$`)
+	mermaidIdentifier = regexp.MustCompile(`^[\p{L}\p{N}_]+`)
+	mermaidClassName  = regexp.MustCompile(`^:::([\p{L}\p{N}_-]+)`)
+	// link with text between its parts, such as: -- text --> or -. text .-> or == text ==>
+	mermaidTextLink = regexp.MustCompile(`^([<xo]?)(--|-\.|==)\s+(.*?)\s*(-{2,}[>xo]|-{3,}|\.+-[>xo]?|={2,}[>xo]|={3,})`)
+	mermaidLink     = regexp.MustCompile(`^([<xo]?)(-{2,}|={2,}|-\.+-|~{3,})([>xo]?)`)
+	mermaidEntity   = regexp.MustCompile(`[#&]#?\w+;`)
+	// arguments of a click statement: quoted strings or words
+	mermaidClickArgument = regexp.MustCompile(`"[^"]*"|\S+`)
+)
+
+// ParseMermaidFlowchart reads a Mermaid flowchart (or graph) and returns it as a directed Graph.
+// The direction header is stored as the "rankdir" attribute, node shapes are mapped onto Graphviz
+// "shape" (and "style") attributes and link variants onto edge attributes such as "style" and "arrowhead".
+// Subgraphs become cluster subgraphs ; their direction statement is stored as the "direction" attribute.
+// A node belongs to the innermost subgraph that lists it, even if it was used before that subgraph.
+// Click links are stored as the "href", "tooltip" and "target" node attributes.
+// The CSS properties of style, classDef, class and linkStyle statements are translated into Graphviz
+// attributes such as "fillcolor", "color", "fontcolor" and "penwidth".
+// Unsupported syntax is reported as a *ParseError with the line number.
+func ParseMermaidFlowchart(r io.Reader) (*Graph, error) {
+	p := &mermaidParser{
+		graph:     NewGraph(Directed),
+		nodes:     map[string]Node{},
+		classDefs: map[string]string{},
+	}
+	p.scopes = []*Graph{p.graph}
+	if err := p.parse(r); err != nil {
+		return nil, err
+	}
+	return p.graph, nil
+}
+
+type mermaidStatement struct {
+	text         string
+	line, column int
+}
+
+// mermaidStyling is a style, class or linkStyle statement that is applied after all elements are known.
+type mermaidStyling struct {
+	mermaidStatement
+	kind    string
+	targets []string
+	value   string
+}
+
+type mermaidParser struct {
+	graph     *Graph
+	scopes    []*Graph
+	nodes     map[string]Node
+	edges     []Edge
+	classDefs map[string]string
+	stylings  []mermaidStyling
+	header    bool
+}
+
+func (p *mermaidParser) errorf(stmt mermaidStatement, offset int, format string, args ...interface{}) error {
+	column := stmt.column + utf8.RuneCountInString(stmt.text[:offset])
+	return &ParseError{Line: stmt.line, Column: column, Message: fmt.Sprintf(format, args...)}
+}
+
+func (p *mermaidParser) parse(r io.Reader) error {
+	lines := bufio.NewScanner(r)
+	line := 0
+	for lines.Scan() {
+		line++
+		for _, stmt := range splitMermaidLine(lines.Text(), line) {
+			if err := p.statement(stmt); err != nil {
+				return err
+			}
+		}
+	}
+	if err := lines.Err(); err != nil {
+		return err
+	}
+	if !p.header {
+		return &ParseError{Line: line, Message: "missing flowchart or graph header"}
+	}
+	if len(p.scopes) > 1 {
+		return &ParseError{Line: line, Message: "missing end of subgraph"}
+	}
+	return p.applyStylings()
+}
+
+// splitMermaidLine returns the statements of a line separated by semicolons, without comments.
+func splitMermaidLine(text string, line int) (list []mermaidStatement) {
+	if strings.HasPrefix(strings.TrimSpace(text), "%%") {
+		return
+	}
+	start, quoted := 0, false
+	add := func(end int) {
+		part := text[start:end]
+		trimmed := strings.TrimLeftFunc(part, unicode.IsSpace)
+		column := 1 + utf8.RuneCountInString(text[:start]) + utf8.RuneCountInString(part) - utf8.RuneCountInString(trimmed)
+		trimmed = strings.TrimRightFunc(trimmed, unicode.IsSpace)
+		if len(trimmed) > 0 {
+			list = append(list, mermaidStatement{text: trimmed, line: line, column: column})
+		}
+	}
+	for i, r := range text {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == ';' && !quoted:
+			add(i)
+			start = i + 1
+		}
+	}
+	add(len(text))
+	return
+}
+
+func (p *mermaidParser) current() *Graph {
+	return p.scopes[len(p.scopes)-1]
+}
+
+func (p *mermaidParser) statement(stmt mermaidStatement) error {
+	if !p.header {
+		m := mermaidHeader.FindStringSubmatch(stmt.text)
+		if m == nil {
+			return p.errorf(stmt, 0, "expected flowchart or graph header")
+		}
+		p.header = true
+		if len(m[3]) > 0 {
+			p.graph.SetAttribute("rankdir", rankdirOf(m[3]))
+		}
+		return nil
+	}
+	keyword := strings.Fields(stmt.text)[0]
+	rest := strings.TrimSpace(strings.TrimPrefix(stmt.text, keyword))
+	switch keyword {
+	case "subgraph":
+		return p.subgraph(stmt, rest)
+	case "end":
+		if len(p.scopes) == 1 {
+			return p.errorf(stmt, 0, "end without subgraph")
+		}
+		p.scopes = p.scopes[:len(p.scopes)-1]
+		return nil
+	case "direction":
+		m := mermaidDirection.FindStringSubmatch(stmt.text)
+		if m == nil {
+			return p.errorf(stmt, 0, "invalid direction %q", rest)
+		}
+		if len(p.scopes) == 1 {
+			p.graph.SetAttribute("rankdir", rankdirOf(m[1]))
+		} else {
+			p.current().SetAttribute("direction", m[1])
+		}
+		return nil
+	case "style", "class", "linkStyle":
+		return p.styling(stmt, keyword, rest)
+	case "classDef":
+		fields := strings.Fields(rest)
+		if len(fields) < 2 {
+			return p.errorf(stmt, 0, "classDef requires a name and styles")
+		}
+		for _, name := range strings.Split(fields[0], ",") {
+			p.classDefs[name] = strings.TrimSpace(strings.TrimPrefix(rest, fields[0]))
+		}
+		return nil
+	case "click":
+		return p.click(stmt, rest)
+	case "callback", "accTitle", "accDescr":
+		return p.errorf(stmt, 0, "unsupported statement %q", keyword)
+	}
+	return p.chain(stmt)
+}
+
+// rankdirOf returns the Graphviz rankdir for a Mermaid direction.
+func rankdirOf(direction string) string {
+	if direction == "TD" {
+		return "TB"
+	}
+	return direction
+}
+
+// subgraph handles: subgraph id | subgraph id [title] | subgraph id["title"] | subgraph "title"
+func (p *mermaidParser) subgraph(stmt mermaidStatement, rest string) error {
+	if len(rest) == 0 {
+		return p.errorf(stmt, len(stmt.text), "missing subgraph identifier")
+	}
+	id, title := rest, rest
+	if strings.HasPrefix(rest, `"`) {
+		id = unquoteMermaid(rest)
+		title = id
+	} else if open := strings.Index(rest, "["); open > 0 {
+		if !strings.HasSuffix(rest, "]") {
+			return p.errorf(stmt, len(stmt.text), "missing ] after subgraph title")
+		}
+		id = strings.TrimSpace(rest[:open])
+		title = unquoteMermaid(strings.TrimSpace(rest[open+1 : len(rest)-1]))
+	}
+	sub := p.current().Subgraph(id, ClusterOption{})
+	sub.Label(title)
+	p.scopes = append(p.scopes, sub)
+	return nil
+}
+
+// click handles: click id [href] "url" ["tooltip"] [target]
+// The link is stored as the "href" attribute of the node ; callbacks are not supported.
+func (p *mermaidParser) click(stmt mermaidStatement, rest string) error {
+	args := mermaidClickArgument.FindAllString(rest, -1)
+	if len(args) > 1 && args[1] == "href" {
+		args = append(args[:1], args[2:]...)
+	}
+	if len(args) < 2 || !strings.HasPrefix(args[1], `"`) {
+		return p.errorf(stmt, 0, "unsupported click statement %q", rest)
+	}
+	n, ok := p.nodes[args[0]]
+	if !ok {
+		return p.errorf(stmt, 0, "unknown node %q", args[0])
+	}
+	n.SetAttribute("href", unquoteMermaid(args[1]))
+	args = args[2:]
+	if len(args) > 0 && strings.HasPrefix(args[0], `"`) {
+		n.SetAttribute("tooltip", unquoteMermaid(args[0]))
+		args = args[1:]
+	}
+	if len(args) > 0 {
+		n.SetAttribute("target", args[0])
+		args = args[1:]
+	}
+	if len(args) > 0 {
+		return p.errorf(stmt, 0, "unsupported click statement %q", rest)
+	}
+	return nil
+}
+
+func (p *mermaidParser) styling(stmt mermaidStatement, kind, rest string) error {
+	fields := strings.Fields(rest)
+	if len(fields) < 2 {
+		return p.errorf(stmt, 0, "%s requires targets and a value", kind)
+	}
+	s := mermaidStyling{mermaidStatement: stmt, kind: kind, targets: strings.Split(fields[0], ",")}
+	s.value = strings.TrimSpace(strings.TrimPrefix(rest, fields[0]))
+	p.stylings = append(p.stylings, s)
+	return nil
+}
+
+// chain parses: nodes (link nodes)* where nodes is: node (& node)*
+func (p *mermaidParser) chain(stmt mermaidStatement) error {
+	offset := 0
+	var previous []Node
+	var link mermaidLinkInfo
+	for {
+		group := []Node{}
+		for {
+			n, next, err := p.node(stmt, offset)
+			if err != nil {
+				return err
+			}
+			group = append(group, n)
+			offset = skipSpaces(stmt.text, next)
+			if !strings.HasPrefix(stmt.text[offset:], "&") {
+				break
+			}
+			offset = skipSpaces(stmt.text, offset+1)
+		}
+		for _, from := range previous {
+			for _, to := range group {
+				p.edges = append(p.edges, link.apply(p.current().Edge(from, to)))
+			}
+		}
+		if offset == len(stmt.text) {
+			return nil
+		}
+		next, ok := parseMermaidLink(stmt.text[offset:])
+		if !ok {
+			return p.errorf(stmt, offset, "unsupported syntax %q", stmt.text[offset:])
+		}
+		link = next
+		offset = skipSpaces(stmt.text, offset+link.length)
+		if offset == len(stmt.text) {
+			return p.errorf(stmt, offset, "missing node after link")
+		}
+		previous = group
+	}
+}
+
+func skipSpaces(text string, offset int) int {
+	for offset < len(text) && (text[offset] == ' ' || text[offset] == '\t') {
+		offset++
+	}
+	return offset
+}
+
+// node parses: id [ shape-open text shape-close ] [ :::class ] and returns the offset after it.
+func (p *mermaidParser) node(stmt mermaidStatement, offset int) (Node, int, error) {
+	text := stmt.text
+	id := mermaidIdentifier.FindString(text[offset:])
+	if len(id) == 0 {
+		return Node{}, offset, p.errorf(stmt, offset, "expected node identifier")
+	}
+	n, ok := p.nodes[id]
+	if !ok {
+		n = p.current().Node(id)
+		p.nodes[id] = n
+	} else if p.isEnclosing(n.graph) {
+		// like Mermaid, a node belongs to the innermost subgraph that lists it
+		n = p.graph.MoveNode(n, p.current())
+		p.nodes[id] = n
+	}
+	offset += len(id)
+	for _, each := range mermaidNodeShapes {
+		if !strings.HasPrefix(text[offset:], each.open) {
+			continue
+		}
+		start := offset + len(each.open)
+		end := -1
+		if strings.HasPrefix(text[start:], `"`) {
+			if q := strings.Index(text[start+1:], `"`); q >= 0 && strings.HasPrefix(text[start+1+q+1:], each.close) {
+				end = start + 1 + q + 1
+			}
+		} else if c := strings.Index(text[start:], each.close); c >= 0 {
+			end = start + c
+		}
+		if end == -1 {
+			// try another shape with the same opening
+			continue
+		}
+		n.SetAttribute("label", unquoteMermaid(strings.TrimSpace(text[start:end])))
+		n.SetAttributes(toInterfaces(each.attributes)...)
+		offset = end + len(each.close)
+		break
+	}
+	if m := mermaidClassName.FindStringSubmatch(text[offset:]); m != nil {
+		p.stylings = append(p.stylings, mermaidStyling{mermaidStatement: stmt, kind: "class", targets: []string{id}, value: m[1]})
+		offset += len(m[0])
+	}
+	return n, offset, nil
+}
+
+// isEnclosing returns whether the graph is one of the open scopes around the current one.
+func (p *mermaidParser) isEnclosing(g *Graph) bool {
+	for _, each := range p.scopes[:len(p.scopes)-1] {
+		if each == g {
+			return true
+		}
+	}
+	return false
+}
+
+func toInterfaces(list []string) []interface{} {
+	result := make([]interface{}, len(list))
+	for i, each := range list {
+		result[i] = each
+	}
+	return result
+}
+
+// unquoteMermaid removes enclosing quotes and Markdown backticks and resolves entity codes.
+func unquoteMermaid(s string) string {
+	if len(s) >= 2 && strings.HasPrefix(s, `"`) && strings.HasSuffix(s, `"`) {
+		s = s[1 : len(s)-1]
+	}
+	if len(s) >= 2 && strings.HasPrefix(s, "`") && strings.HasSuffix(s, "`") {
+		s = s[1 : len(s)-1]
+	}
+	// resolve Mermaid (#name;) and HTML (&name;) entities in a single pass so the result is not unescaped again
+	return mermaidEntity.ReplaceAllStringFunc(s, func(entity string) string {
+		if strings.HasPrefix(entity, "&") {
+			return html.UnescapeString(entity)
+		}
+		name := entity[1 : len(entity)-1]
+		if _, err := strconv.Atoi(name); err == nil {
+			return html.UnescapeString("&#" + name + ";")
+		}
+		return html.UnescapeString("&" + name + ";")
+	})
+}
+
+// mermaidLinkInfo describes a parsed link between nodes.
+type mermaidLinkInfo struct {
+	length     int // number of characters of the link including its text
+	start, end string
+	line       string // "-", "=", "." or "~"
+	dashes     int
+	label      string
+}
+
+// parseMermaidLink parses a link (with optional text) at the start of s.
+func parseMermaidLink(s string) (mermaidLinkInfo, bool) {
+	info := mermaidLinkInfo{}
+	if m := mermaidTextLink.FindStringSubmatch(s); m != nil {
+		info.start, info.label = m[1], m[3]
+		closer := m[4]
+		switch {
+		case strings.HasPrefix(m[2], "-.") || strings.HasPrefix(closer, "."):
+			info.line = "."
+		case m[2] == "==":
+			info.line = "="
+		default:
+			info.line = "-"
+		}
+		if last := closer[len(closer)-1]; last == '>' || last == 'x' || last == 'o' {
+			info.end = string(last)
+			closer = closer[:len(closer)-1]
+		}
+		info.dashes = len(closer)
+		info.length = len(m[0])
+	} else if m := mermaidLink.FindStringSubmatch(s); m != nil {
+		info.start, info.end = m[1], m[3]
+		body := m[2]
+		info.length = len(m[0])
+		// x or o directly followed by an identifier is the start of the next node
+		if (info.end == "x" || info.end == "o") && info.length < len(s) && mermaidIdentifier.MatchString(s[info.length:]) {
+			info.end = ""
+			info.length--
+		}
+		switch {
+		case strings.Contains(body, "."):
+			info.line = "."
+		case strings.HasPrefix(body, "="):
+			info.line = "="
+		case strings.HasPrefix(body, "~"):
+			info.line = "~"
+		default:
+			info.line = "-"
+		}
+		info.dashes = len(body)
+		if info.end == "" && info.line != "~" {
+			// without arrow the line has one extra dash, e.g. --- versus -->
+			info.dashes--
+		}
+	} else {
+		return info, false
+	}
+	// optional |text| after the link
+	rest := skipSpaces(s, info.length)
+	if strings.HasPrefix(s[rest:], "|") {
+		if end := strings.Index(s[rest+1:], "|"); end >= 0 {
+			info.label = s[rest+1 : rest+1+end]
+			info.length = rest + 1 + end + 1
+		}
+	}
+	info.label = unquoteMermaid(strings.TrimSpace(info.label))
+	return info, true
+}
+
+var mermaidArrowNames = map[string]string{">": "normal", "x": "tee", "o": "odot", "": "none"}
+
+// apply sets the edge attributes that represent this link.
+func (l mermaidLinkInfo) apply(e Edge) Edge {
+	if len(l.label) > 0 {
+		e.Label(l.label)
+	}
+	switch l.line {
+	case ".":
+		e.Dotted()
+	case "=":
+		e.Bold()
+	case "~":
+		e.SetAttribute("style", "invis")
+	}
+	if l.line != "~" {
+		if l.end != ">" {
+			e.SetAttribute("arrowhead", mermaidArrowNames[l.end])
+		}
+		if len(l.start) > 0 {
+			e.SetAttribute("dir", "both")
+			if l.start != "<" {
+				e.SetAttribute("arrowtail", mermaidArrowNames[l.start])
+			}
+		}
+	}
+	if l.dashes > 2 {
+		e.SetAttribute("minlen", strconv.Itoa(l.dashes-1))
+	}
+	return e
+}
+
+// applyStylings processes all style, class and linkStyle statements.
+func (p *mermaidParser) applyStylings() error {
+	if css, ok := p.classDefs["default"]; ok {
+		ids := []string{}
+		for id := range p.nodes {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			applyCSS(p.nodes[id].AttributesMap, css, false)
+		}
+	}
+	// classes before styles
+	sort.SliceStable(p.stylings, func(i, j int) bool {
+		return p.stylings[i].kind == "class" && p.stylings[j].kind != "class"
+	})
+	for _, each := range p.stylings {
+		switch each.kind {
+		case "class":
+			css, ok := p.classDefs[each.value]
+			if !ok {
+				return p.errorf(each.mermaidStatement, 0, "unknown class %q", each.value)
+			}
+			for _, id := range each.targets {
+				target, ok := p.styleTarget(id)
+				if !ok {
+					return p.errorf(each.mermaidStatement, 0, "unknown node or subgraph %q", id)
+				}
+				target.SetAttribute("class", each.value)
+				applyCSS(target, css, false)
+			}
+		case "style":
+			for _, id := range each.targets {
+				target, ok := p.styleTarget(id)
+				if !ok {
+					return p.errorf(each.mermaidStatement, 0, "unknown node or subgraph %q", id)
+				}
+				applyCSS(target, each.value, false)
+			}
+		case "linkStyle":
+			for _, index := range each.targets {
+				if index == "default" {
+					for _, e := range p.edges {
+						applyCSS(e.AttributesMap, each.value, true)
+					}
+					continue
+				}
+				i, err := strconv.Atoi(index)
+				if err != nil || i < 0 || i >= len(p.edges) {
+					return p.errorf(each.mermaidStatement, 0, "invalid link index %q", index)
+				}
+				applyCSS(p.edges[i].AttributesMap, each.value, true)
+			}
+		}
+	}
+	return nil
+}
+
+func (p *mermaidParser) styleTarget(id string) (AttributesMap, bool) {
+	if n, ok := p.nodes[id]; ok {
+		return n.AttributesMap, true
+	}
+	if sub, ok := p.graph.findSubgraphDeep(id); ok {
+		return sub.AttributesMap, true
+	}
+	return AttributesMap{}, false
+}
+
+// findSubgraphDeep returns the subgraph with the given key from this graph or any of its subgraphs.
+func (g *Graph) findSubgraphDeep(key string) (*Graph, bool) {
+	if sub, ok := g.subgraphs[key]; ok {
+		return sub, true
+	}
+	for _, each := range g.subgraphs {
+		if sub, ok := each.findSubgraphDeep(key); ok {
+			return sub, true
+		}
+	}
+	return nil, false
+}
+
+// applyCSS translates CSS properties (as used in Mermaid) into Graphviz attributes.
+func applyCSS(a AttributesMap, css string, isEdge bool) {
+	for _, each := range strings.Split(css, ",") {
+		kv := strings.SplitN(each, ":", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		key, value := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+		value = strings.TrimSpace(strings.TrimSuffix(value, "!important"))
+		switch key {
+		case "fill":
+			if isEdge || value == "none" {
+				continue
+			}
+			addStyle(a, "filled")
+			a.SetAttribute("fillcolor", value)
+		case "stroke":
+			a.SetAttribute("color", value)
+		case "stroke-width":
+			a.SetAttribute("penwidth", strings.TrimSuffix(value, "px"))
+		case "stroke-dasharray":
+			addStyle(a, "dashed")
+		case "color":
+			a.SetAttribute("fontcolor", value)
+		case "font-size":
+			a.SetAttribute("fontsize", strings.TrimSuffix(value, "px"))
+		case "font-family":
+			a.SetAttribute("fontname", strings.Trim(value, `'"`))
+		}
+	}
+}
+
+// addStyle adds a value to the comma separated "style" attribute unless present.
+func addStyle(a AttributesMap, style string) {
+	current, _ := a.Value("style").(string)
+	if len(current) == 0 {
+		a.SetAttribute("style", style)
+		return
+	}
+	for _, each := range strings.Split(current, ",") {
+		if strings.TrimSpace(each) == style {
+			return
+		}
+	}
+	a.SetAttribute("style", current+","+style)
+}
@@ -0,0 +1,280 @@
+package dot
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func parseMermaid(t *testing.T, src string) *Graph {
+	t.Helper()
+	g, err := ParseMermaidFlowchart(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestParseMermaidHeader(t *testing.T) {
+	for src, want := range map[string]interface{}{
+		"flowchart LR": "LR",
+		"graph TD;":    "TB",
+		"flowchart":    nil,
+	} {
+		g := parseMermaid(t, src)
+		if got := g.Value("rankdir"); got != want {
+			t.Errorf("%q: got [%v] want [%v]", src, got, want)
+		}
+		if !g.IsDirected() {
+			t.Errorf("%q: expected directed graph", src)
+		}
+	}
+}
+
+func TestParseMermaidShapes(t *testing.T) {
+	g := parseMermaid(t, `flowchart TB
+	a[rect] --> b(round)
+	c([stadium]); d[[sub]]; e[(cyl)]; f((circle)); g>asym]; h{rhombus}; i{{hex}}
+	j[/para/]; k[\paraalt\]; l[/trap\]; m[\trapalt/]; n(((double))); o["quoted [text]"]`)
+	for id, want := range map[string]string{
+		"a": "box", "b": "box", "c": "box", "d": "box", "e": "cylinder", "f": "circle",
+		"g": "cds", "h": "diamond", "i": "hexagon", "j": "parallelogram", "k": "parallelogram",
+		"l": "trapezium", "m": "invtrapezium", "n": "doublecircle", "o": "box",
+	} {
+		n, ok := g.FindNodeById(id)
+		if !ok {
+			t.Errorf("missing node %s", id)
+			continue
+		}
+		if got := n.Value("shape"); got != want {
+			t.Errorf("%s: got [%v] want [%v]", id, got, want)
+		}
+	}
+	b, _ := g.FindNodeById("b")
+	if got, want := b.Value("style"), "rounded"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	o, _ := g.FindNodeById("o")
+	if got, want := o.Value("label"), "quoted [text]"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	// shapes map back when writing Mermaid
+	if got, want := flatten(MermaidFlowchart(g, MermaidTopDown)), `n8{"rhombus"};`; !strings.Contains(got, want) {
+		t.Errorf("got [%v] want to contain [%v]", got, want)
+	}
+}
+
+func TestParseMermaidLinks(t *testing.T) {
+	g := parseMermaid(t, `graph LR
+	a -->|yes| b
+	a -- no --> c
+	a -.-> d
+	a ==> e
+	a --- f
+	a ~~~ g
+	a --x h
+	a <--> i
+	a ----> j
+	a -. maybe .-> k`)
+	for _, each := range []struct {
+		to    string
+		key   string
+		value interface{}
+	}{
+		{"b", "label", "yes"},
+		{"c", "label", "no"},
+		{"d", "style", "dotted"},
+		{"e", "style", "bold"},
+		{"f", "arrowhead", "none"},
+		{"g", "style", "invis"},
+		{"h", "arrowhead", "tee"},
+		{"i", "dir", "both"},
+		{"j", "minlen", "3"},
+		{"k", "label", "maybe"},
+		{"k", "style", "dotted"},
+	} {
+		to, _ := g.FindNodeById(each.to)
+		edges := g.FindEdges(g.nodes["a"], to)
+		if len(edges) != 1 {
+			t.Errorf("%s: got %d edges", each.to, len(edges))
+			continue
+		}
+		if got := edges[0].Value(each.key); got != each.value {
+			t.Errorf("%s %s: got [%v] want [%v]", each.to, each.key, got, each.value)
+		}
+	}
+}
+
+func TestParseMermaidChains(t *testing.T) {
+	g := parseMermaid(t, "flowchart\n a & b --> c --> d & e")
+	if got, want := flatten(g.String()), `digraph  {n1[label="a"];n2[label="b"];n3[label="c"];n4[label="d"];n5[label="e"];n1->n3;n2->n3;n3->n4;n3->n5;}`; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestParseMermaidSubgraphs(t *testing.T) {
+	g := parseMermaid(t, `flowchart LR
+	%% comment
+	start --> a1
+	subgraph one [First step]
+		direction TB
+		a1 --> a2
+		subgraph inner["Inner"]
+			a3
+		end
+	end
+	a2 --> a3`)
+	one, ok := g.FindSubgraph("one")
+	if !ok {
+		t.Fatal("missing subgraph one")
+	}
+	if got, want := one.Value("label"), "First step"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := one.Value("direction"), "TB"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if !strings.HasPrefix(one.ID(), "cluster_") {
+		t.Errorf("expected cluster, got %s", one.ID())
+	}
+	inner, ok := one.FindSubgraph("inner")
+	if !ok {
+		t.Fatal("missing subgraph inner")
+	}
+	if got, want := inner.Value("label"), "Inner"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	// a1 is first mentioned outside the subgraph and then moved into it
+	a1, _ := g.FindNodeById("a1")
+	if got, want := a1.Graph(), one; got != want {
+		t.Errorf("a1 got [%v] want [%v]", got.ID(), want.ID())
+	}
+	// a3 is mentioned again after its subgraph ends
+	a3, _ := g.FindNodeById("a3")
+	if got, want := a3.Graph(), inner; got != want {
+		t.Errorf("a3 got [%v] want [%v]", got.ID(), want.ID())
+	}
+	start, _ := g.FindNodeById("start")
+	if got, want := len(start.EdgesTo(a1)), 1; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestParseMermaidStyles(t *testing.T) {
+	g := parseMermaid(t, `flowchart TD
+	a:::hot --> b
+	b --> c
+	classDef hot fill:#f96,stroke:#333,stroke-width:4px
+	class c hot
+	style b fill:#bbf,color:#fff,stroke-dasharray: 5 5
+	linkStyle 1 stroke:#ff3,stroke-width:2px`)
+	a, _ := g.FindNodeById("a")
+	for key, want := range map[string]string{"style": "filled", "fillcolor": "#f96", "color": "#333", "penwidth": "4", "class": "hot"} {
+		if got := a.Value(key); got != want {
+			t.Errorf("a %s: got [%v] want [%v]", key, got, want)
+		}
+	}
+	c, _ := g.FindNodeById("c")
+	if got, want := c.Value("class"), "hot"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	b, _ := g.FindNodeById("b")
+	for key, want := range map[string]string{"style": "filled,dashed", "fillcolor": "#bbf", "fontcolor": "#fff"} {
+		if got := b.Value(key); got != want {
+			t.Errorf("b %s: got [%v] want [%v]", key, got, want)
+		}
+	}
+	e := g.FindEdges(b, c)[0]
+	if got, want := e.Value("color"), "#ff3"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := e.Value("penwidth"), "2"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestParseMermaidEntities(t *testing.T) {
+	g := parseMermaid(t, `flowchart LR
+	a["say #quot;hi#quot; &amp; #9829;"]`)
+	a, _ := g.FindNodeById("a")
+	if got, want := a.Value("label"), "say \"hi\" & ♥"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	g = parseMermaid(t, `flowchart LR
+	b["&amp;lt; #35;quot;"]`)
+	b, _ := g.FindNodeById("b")
+	if got, want := b.Value("label"), "&lt; #quot;"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestParseMermaidErrors(t *testing.T) {
+	for _, each := range []struct {
+		src          string
+		line, column int
+	}{
+		{"sequenceDiagram", 1, 1},
+		{"flowchart LR\n  a --> ", 2, 8},
+		{"flowchart LR\n  a --> b\n  click a callback", 3, 3},
+		{"flowchart LR\n  a --> b\n  click c href \"https://example.com\"", 3, 3},
+		{"flowchart LR\n  a ?? b", 2, 5},
+		{"flowchart LR\n  end", 2, 3},
+		{"flowchart LR\n  subgraph x\n  a", 3, 0},
+		{"flowchart LR\n  a\n  style q fill:#fff", 3, 3},
+		{"flowchart LR\n  a\n  linkStyle 4 stroke:#fff", 3, 3},
+	} {
+		_, err := ParseMermaidFlowchart(strings.NewReader(each.src))
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Errorf("%q: expected ParseError, got %v", each.src, err)
+			continue
+		}
+		if perr.Line != each.line || perr.Column != each.column {
+			t.Errorf("%q: got %d:%d want %d:%d (%v)", each.src, perr.Line, perr.Column, each.line, each.column, perr)
+		}
+	}
+}
+
+func TestParseMermaidWrittenSubgraphs(t *testing.T) {
+	di := NewGraph(Directed)
+	outer := di.Subgraph("outer")
+	outer.SetAttribute("direction", "LR")
+	inner := outer.Subgraph("inner")
+	outer.Node("a").Edge(inner.Node("b"))
+	g := parseMermaid(t, MermaidFlowchart(di, MermaidTopDown))
+	parsedOuter, ok := g.FindSubgraph("outer")
+	if !ok {
+		t.Fatal("missing subgraph outer")
+	}
+	if got, want := parsedOuter.Value("direction"), "LR"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if _, ok := parsedOuter.FindSubgraph("inner"); !ok {
+		t.Error("missing subgraph inner")
+	}
+}
+
+func TestParseMermaidClick(t *testing.T) {
+	di := NewGraph(Directed)
+	di.Node("a").SetAttribute("href", "https://example.com").SetAttribute("tooltip", `say "hi"`)
+	di.Node("b").SetAttribute("href", "https://example.org").SetAttribute("target", "_blank")
+	g := parseMermaid(t, MermaidFlowchartWithOptions(di, MermaidOptions{Interactive: true}))
+	a, _ := g.FindNodeById("n1")
+	if got, want := a.Value("href"), "https://example.com"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := a.Value("tooltip"), `say "hi"`; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	b, _ := g.FindNodeById("n2")
+	if got, want := b.Value("target"), "_blank"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	g = parseMermaid(t, `flowchart LR
+	c
+	click c "https://example.net"`)
+	c, _ := g.FindNodeById("c")
+	if got, want := c.Value("href"), "https://example.net"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
@@ -0,0 +1,100 @@
+package dot
+
+// Shapes of the extended Mermaid syntax, see https://mermaid.js.org/syntax/flowchart.html#complete-list-of-new-shapes
+// Without the ExtendedShapes option these are written using the closest classic brackets.
+var (
+	MermaidShapeRect                = shape{"[", "]", "rect"}
+	MermaidShapeCard                = shape{"[", "]", "notch-rect"}
+	MermaidShapeCollate             = shape{"[", "]", "hourglass"}
+	MermaidShapeComLink             = shape{"[", "]", "bolt"}
+	MermaidShapeComment             = shape{"[", "]", "brace"}
+	MermaidShapeCommentRight        = shape{"[", "]", "brace-r"}
+	MermaidShapeCommentBraces       = shape{"[", "]", "braces"}
+	MermaidShapeDelay               = shape{"(", ")", "delay"}
+	MermaidShapeDirectAccessStorage = shape{"[(", ")]", "h-cyl"}
+	MermaidShapeDiskStorage         = shape{"[(", ")]", "lin-cyl"}
+	MermaidShapeDisplay             = shape{"[", "]", "curv-trap"}
+	MermaidShapeDividedProcess      = shape{"[", "]", "div-rect"}
+	MermaidShapeDocument            = shape{"[", "]", "doc"}
+	MermaidShapeExtract             = shape{"[", "]", "tri"}
+	MermaidShapeFork                = shape{"[", "]", "fork"}
+	MermaidShapeInternalStorage     = shape{"[", "]", "win-pane"}
+	MermaidShapeJunction            = shape{"((", "))", "f-circ"}
+	MermaidShapeLinedDocument       = shape{"[", "]", "lin-doc"}
+	MermaidShapeLinedProcess        = shape{"[", "]", "lin-rect"}
+	MermaidShapeLoopLimit           = shape{"[", "]", "notch-pent"}
+	MermaidShapeManualFile          = shape{"[", "]", "flip-tri"}
+	MermaidShapeManualInput         = shape{"[", "]", "sl-rect"}
+	MermaidShapeMultiDocument       = shape{"[", "]", "docs"}
+	MermaidShapeMultiProcess        = shape{"[", "]", "st-rect"}
+	MermaidShapePaperTape           = shape{">", "]", "flag"}
+	MermaidShapeSmallCircle         = shape{"((", "))", "sm-circ"}
+	MermaidShapeDoubleCircle        = shape{"(((", ")))", "dbl-circ"}
+	MermaidShapeFramedCircle        = shape{"(((", ")))", "fr-circ"}
+	MermaidShapeStoredData          = shape{"[", "]", "bow-rect"}
+	MermaidShapeSummary             = shape{"((", "))", "cross-circ"}
+	MermaidShapeTaggedDocument      = shape{"[", "]", "tag-doc"}
+	MermaidShapeTaggedProcess       = shape{"[", "]", "tag-rect"}
+	MermaidShapeText                = shape{"[", "]", "text"}
+)
+
+// mermaidShapeCatalogue maps the short names and aliases of the extended syntax to a shape.
+var mermaidShapeCatalogue = func() map[string]shape {
+	m := map[string]shape{}
+	for _, each := range []struct {
+		shape   shape
+		aliases []string
+	}{
+		{MermaidShapeRect, []string{"proc", "process", "rectangle"}},
+		{MermaidShapeRound, []string{"event"}},
+		{MermaidShapeStadium, []string{"pill", "terminal"}},
+		{MermaidShapeSubroutine, []string{"framed-rectangle", "subproc", "subprocess", "subroutine"}},
+		{MermaidShapeCylinder, []string{"cylinder", "database", "db"}},
+		{MermaidShapeCircle, []string{"circ"}},
+		{MermaidShapeAsymmetric, nil},
+		{MermaidShapeRhombus, []string{"decision", "diamond", "question"}},
+		{MermaidShapeTrapezoid, []string{"priority", "trapezoid", "trapezoid-bottom"}},
+		{MermaidShapeTrapezoidAlt, []string{"inv-trapezoid", "manual", "trapezoid-top"}},
+		{MermaidShapeHexagon, []string{"hexagon", "prepare"}},
+		{MermaidShapeParallelogram, []string{"in-out", "lean-right"}},
+		{MermaidShapeParallelogramAlt, []string{"lean-left", "out-in"}},
+		{MermaidShapeCard, []string{"card", "notched-rectangle"}},
+		{MermaidShapeCollate, []string{"collate", "hourglass"}},
+		{MermaidShapeComLink, []string{"com-link", "lightning-bolt"}},
+		{MermaidShapeComment, []string{"brace-l", "comment"}},
+		{MermaidShapeCommentRight, nil},
+		{MermaidShapeCommentBraces, nil},
+		{MermaidShapeDelay, []string{"half-rounded-rectangle"}},
+		{MermaidShapeDirectAccessStorage, []string{"das", "horizontal-cylinder"}},
+		{MermaidShapeDiskStorage, []string{"disk", "lined-cylinder"}},
+		{MermaidShapeDisplay, []string{"curved-trapezoid", "display"}},
+		{MermaidShapeDividedProcess, []string{"div-proc", "divided-process", "divided-rectangle"}},
+		{MermaidShapeDocument, []string{"document"}},
+		{MermaidShapeExtract, []string{"extract", "triangle"}},
+		{MermaidShapeFork, []string{"join"}},
+		{MermaidShapeInternalStorage, []string{"internal-storage", "window-pane"}},
+		{MermaidShapeJunction, []string{"filled-circle", "junction"}},
+		{MermaidShapeLinedDocument, []string{"lined-document"}},
+		{MermaidShapeLinedProcess, []string{"lin-proc", "lined-process", "lined-rectangle", "shaded-process"}},
+		{MermaidShapeLoopLimit, []string{"loop-limit", "notched-pentagon"}},
+		{MermaidShapeManualFile, []string{"flipped-triangle", "manual-file"}},
+		{MermaidShapeManualInput, []string{"manual-input", "sloped-rectangle"}},
+		{MermaidShapeMultiDocument, []string{"documents", "st-doc", "stacked-document"}},
+		{MermaidShapeMultiProcess, []string{"processes", "procs", "stacked-rectangle"}},
+		{MermaidShapePaperTape, []string{"paper-tape"}},
+		{MermaidShapeSmallCircle, []string{"small-circle", "start"}},
+		{MermaidShapeDoubleCircle, []string{"double-circle"}},
+		{MermaidShapeFramedCircle, []string{"framed-circle", "stop"}},
+		{MermaidShapeStoredData, []string{"bow-tie-rectangle", "stored-data"}},
+		{MermaidShapeSummary, []string{"crossed-circle", "summary"}},
+		{MermaidShapeTaggedDocument, []string{"tagged-document"}},
+		{MermaidShapeTaggedProcess, []string{"tag-proc", "tagged-process", "tagged-rectangle"}},
+		{MermaidShapeText, nil},
+	} {
+		m[each.shape.name] = each.shape
+		for _, alias := range each.aliases {
+			m[alias] = each.shape
+		}
+	}
+	return m
+}()
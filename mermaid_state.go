@@ -0,0 +1,131 @@
+package dot
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MermaidStateDiagram returns the graph as a Mermaid stateDiagram-v2.
+// Nodes are written as states, edges as transitions (with their label) and subgraphs as composite states.
+// A transition is written in the innermost composite that contains both states ; a state in a nested composite is
+// replaced by the composite that it is in.
+// The "rankdir" attribute of the graph and the "direction" attribute of a subgraph set the direction.
+//
+// Pseudo-states [*] are written for:
+//   - nodes with shape "point" ; these replace the node in all its transitions
+//   - nodes with the attribute "state" set to "start" (transition from [*]) or "end" (transition to [*])
+//   - nodes with shape "doublecircle" (transition to [*])
+//
+// The "comment" attribute of a node is written as a note.
+func MermaidStateDiagram(g *Graph) string {
+	sb := new(strings.Builder)
+	sb.WriteString("stateDiagram-v2\n")
+	w := &mermaidWriter{
+		sb:           sb,
+		subgraphIDs:  map[*Graph]string{},
+		usedIDs:      map[string]bool{},
+		edgesOfScope: map[*Graph][]Edge{},
+		directed:     true,
+	}
+	w.collect(g)
+	if rankdir := stringAttribute(g.AttributesMap, "rankdir"); len(rankdir) > 0 {
+		fmt.Fprintf(sb, "\tdirection %s\n", rankdir)
+	}
+	w.writeStates(g, 1)
+	return sb.String()
+}
+
+// writeStates writes the states, composite states and transitions of a (sub)graph.
+func (w *mermaidWriter) writeStates(g *Graph, depth int) {
+	indent := strings.Repeat("\t", depth)
+	for _, key := range g.sortedNodesKeys() {
+		each := g.nodes[key]
+		if isPseudoState(each) {
+			continue
+		}
+		fmt.Fprintf(w.sb, "%sstate %s as n%d\n", indent, mermaidString(stateText(nodeLabel(each))), each.seq)
+		if comment := stringAttribute(each.AttributesMap, "comment"); len(comment) > 0 {
+			if strings.Contains(comment, "\n") {
+				fmt.Fprintf(w.sb, "%snote right of n%d\n", indent, each.seq)
+				for _, line := range strings.Split(comment, "\n") {
+					fmt.Fprintf(w.sb, "%s\t%s\n", indent, line)
+				}
+				fmt.Fprintf(w.sb, "%send note\n", indent)
+			} else {
+				fmt.Fprintf(w.sb, "%snote right of n%d : %s\n", indent, each.seq, comment)
+			}
+		}
+	}
+	for _, key := range g.sortedSubgraphsKeys() {
+		each := g.subgraphs[key]
+		title := w.subgraphIDs[each]
+		if label := each.Value("label"); label != nil {
+			title = fmt.Sprintf("%v", label)
+		}
+		fmt.Fprintf(w.sb, "%sstate %s as %s {\n", indent, mermaidString(stateText(title)), w.subgraphIDs[each])
+		if direction := each.Value("direction"); direction != nil {
+			fmt.Fprintf(w.sb, "%s\tdirection %v\n", indent, direction)
+		}
+		w.writeStates(each, depth+1)
+		fmt.Fprintf(w.sb, "%s}\n", indent)
+	}
+	// start and end markers
+	for _, key := range g.sortedNodesKeys() {
+		each := g.nodes[key]
+		if isPseudoState(each) {
+			continue
+		}
+		marker := stringAttribute(each.AttributesMap, "state")
+		if marker == "start" {
+			fmt.Fprintf(w.sb, "%s[*] --> n%d\n", indent, each.seq)
+		}
+		if marker == "end" || stringAttribute(each.AttributesMap, "shape") == "doublecircle" {
+			fmt.Fprintf(w.sb, "%sn%d --> [*]\n", indent, each.seq)
+		}
+	}
+	for _, each := range w.edgesOfScope[g] {
+		fmt.Fprintf(w.sb, "%s%s --> %s", indent, w.stateOf(g, each.from), w.stateOf(g, each.to))
+		if label := each.Attribute("label"); label != nil && label != "" {
+			fmt.Fprintf(w.sb, " : %s", stateText(fmt.Sprintf("%v", label)))
+		}
+		w.sb.WriteString("\n")
+	}
+}
+
+// isPseudoState returns whether the node is written as [*].
+func isPseudoState(n Node) bool {
+	return stringAttribute(n.AttributesMap, "shape") == "point"
+}
+
+func stateName(n Node) string {
+	if isPseudoState(n) {
+		return "[*]"
+	}
+	return fmt.Sprintf("n%d", n.seq)
+}
+
+// stateOf returns the name of the state of the composite g that contains the node.
+// Mermaid has no transitions between states of different composites so these go to (or from) the composite itself.
+func (w *mermaidWriter) stateOf(g *Graph, n Node) string {
+	if n.graph == g {
+		return stateName(n)
+	}
+	composite := n.graph
+	for composite.parent != g {
+		composite = composite.parent
+	}
+	return w.subgraphIDs[composite]
+}
+
+// nodeLabel returns the label of a node if it is a string, the identifier otherwise.
+func nodeLabel(n Node) string {
+	if label, ok := n.Attribute("label").(string); ok {
+		return label
+	}
+	return n.id
+}
+
+// stateText replaces line breaks that are not allowed in state names and transition labels.
+func stateText(s string) string {
+	return strings.ReplaceAll(s, "\n", "<br/>")
+}
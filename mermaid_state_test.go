@@ -0,0 +1,90 @@
+package dot
+
+import "testing"
+
+func TestMermaidStateDiagram(t *testing.T) {
+	g := NewGraph(Directed)
+	g.SetAttribute("rankdir", "LR")
+	begin := g.Node("begin").SetAttribute("shape", "point")
+	idle := g.Node("idle").Label("Idle").SetAttribute("comment", "waiting")
+	busy := g.Node("busy").Label("Busy").SetAttribute("state", "end")
+	done := g.Node("done").SetAttribute("shape", "doublecircle")
+	begin.Edge(idle)
+	idle.Edge(busy, "start")
+	busy.Edge(done, "finish")
+	got := MermaidStateDiagram(g)
+	want := `stateDiagram-v2
+	direction LR
+	state "Busy" as n3
+	state "done" as n4
+	state "Idle" as n2
+	note right of n2 : waiting
+	n3 --> [*]
+	n4 --> [*]
+	[*] --> n2
+	n3 --> n4 : finish
+	n2 --> n3 : start
+`
+	if got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestMermaidStateDiagramComposite(t *testing.T) {
+	g := NewGraph(Directed)
+	outer := g.Node("outer")
+	active := g.Subgraph("Active")
+	active.SetAttribute("direction", "TB")
+	first := active.Node("first").SetAttribute("state", "start").SetAttribute("comment", "line 1\nline 2")
+	second := active.Node("second")
+	first.Edge(second, "next")
+	outer.Edge(second)
+	got := MermaidStateDiagram(g)
+	want := `stateDiagram-v2
+	state "outer" as n1
+	state "Active" as Active {
+		direction TB
+		state "first" as n3
+		note right of n3
+			line 1
+			line 2
+		end note
+		state "second" as n4
+		[*] --> n3
+		n3 --> n4 : next
+	}
+	n1 --> Active
+`
+	if got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestMermaidStateDiagramCrossComposite(t *testing.T) {
+	g := NewGraph(Directed)
+	outer := g.Subgraph("Outer")
+	left := outer.Subgraph("Left")
+	right := outer.Subgraph("Right")
+	a := left.Node("a")
+	b := right.Node("b")
+	c := outer.Node("c")
+	a.Edge(b, "cross")
+	c.Edge(a)
+	got := MermaidStateDiagram(g)
+	want := `stateDiagram-v2
+	state "Outer" as Outer {
+		state "c" as n6
+		state "Left" as Left {
+			state "a" as n4
+		}
+		state "Right" as Right {
+			state "b" as n5
+		}
+		Left --> Right : cross
+		n6 --> Left
+	}
+`
+	if got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
@@ -0,0 +1,224 @@
+package dot
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// mermaidStyles collects the CSS of styled nodes and links while writing a diagram.
+// Nodes that share the same CSS get a classDef, links are styled by their index.
+type mermaidStyles struct {
+	nodeCSS   []string
+	nodesOf   map[string][]string
+	linkCSS   []string
+	linksOf   map[string][]string
+	linkCount int
+	// classes defined with Graph.DefineClass, in order of first use
+	classes   []string
+	classCSS  map[string]string
+	membersOf map[string][]string
+}
+
+func newMermaidStyles() *mermaidStyles {
+	return &mermaidStyles{
+		nodesOf:   map[string][]string{},
+		linksOf:   map[string][]string{},
+		classCSS:  map[string]string{},
+		membersOf: map[string][]string{},
+	}
+}
+
+// addClasses registers the node or subgraph (by its Mermaid name) as member of its defined classes.
+// Classes without CSS properties are skipped. A class defined in a subgraph is named after the class and the subgraph
+// so that it can shadow a class with the same name of a parent.
+func (s *mermaidStyles) addClasses(scope *Graph, attributes map[string]interface{}, member string) {
+	for _, each := range classNames(attributes) {
+		class, ok := scope.ClassAttributes(each)
+		if !ok {
+			continue
+		}
+		css := nodeCSS(AttributesMap{attributes: class})
+		if len(css) == 0 {
+			continue
+		}
+		name := mermaidClassID(each)
+		if definer, _ := scope.classDefiner(each); definer.parent != nil {
+			name = mermaidClassID(each + "_" + definer.id)
+		}
+		if _, ok := s.classCSS[name]; !ok {
+			s.classes = append(s.classes, name)
+			s.classCSS[name] = css
+		}
+		s.membersOf[name] = append(s.membersOf[name], member)
+	}
+}
+
+// mermaidClassID replaces the characters that are not allowed in a Mermaid class name.
+func mermaidClassID(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r == '-' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+func (s *mermaidStyles) addNode(name, css string) {
+	if _, ok := s.nodesOf[css]; !ok {
+		s.nodeCSS = append(s.nodeCSS, css)
+	}
+	s.nodesOf[css] = append(s.nodesOf[css], name)
+}
+
+// addLink registers the next written link ; css can be empty.
+func (s *mermaidStyles) addLink(css string) {
+	index := s.linkCount
+	s.linkCount++
+	if len(css) == 0 {
+		return
+	}
+	if _, ok := s.linksOf[css]; !ok {
+		s.linkCSS = append(s.linkCSS, css)
+	}
+	s.linksOf[css] = append(s.linksOf[css], fmt.Sprintf("%d", index))
+}
+
+// write emits the classDef, class, style and linkStyle statements.
+func (s *mermaidStyles) write(sb *strings.Builder) {
+	for _, name := range s.classes {
+		fmt.Fprintf(sb, "\tclassDef %s %s", name, s.classCSS[name])
+		writeEnd(sb)
+		fmt.Fprintf(sb, "\tclass %s %s", strings.Join(s.membersOf[name], ","), name)
+		writeEnd(sb)
+	}
+	classes := 0
+	for _, css := range s.nodeCSS {
+		names := s.nodesOf[css]
+		if len(names) == 1 {
+			fmt.Fprintf(sb, "\tstyle %s %s", names[0], css)
+			writeEnd(sb)
+			continue
+		}
+		classes++
+		fmt.Fprintf(sb, "\tclassDef dotStyle%d %s", classes, css)
+		writeEnd(sb)
+		fmt.Fprintf(sb, "\tclass %s dotStyle%d", strings.Join(names, ","), classes)
+		writeEnd(sb)
+	}
+	for _, css := range s.linkCSS {
+		fmt.Fprintf(sb, "\tlinkStyle %s %s", strings.Join(s.linksOf[css], ","), css)
+		writeEnd(sb)
+	}
+}
+
+// stringAttribute returns the attribute value as a string, empty if absent.
+func stringAttribute(a AttributesMap, key string) string {
+	v := a.Value(key)
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// hasStyle returns whether the comma separated "style" attribute has the value.
+func hasStyle(a AttributesMap, style string) bool {
+	for _, each := range strings.Split(stringAttribute(a, "style"), ",") {
+		if strings.TrimSpace(each) == style {
+			return true
+		}
+	}
+	return false
+}
+
+// mermaidColor returns the first color of a Graphviz color list such as "red:blue;0.3".
+func mermaidColor(value string) string {
+	value = strings.Split(value, ":")[0]
+	return strings.Split(value, ";")[0]
+}
+
+// nodeCSS translates the Graphviz styling attributes of a node into CSS properties.
+// An invisible node has neither fill nor stroke.
+func nodeCSS(a AttributesMap) string {
+	if hasStyle(a, "invis") {
+		return "fill:none,stroke:none"
+	}
+	css := []string{}
+	if fill := stringAttribute(a, "fillcolor"); len(fill) > 0 {
+		css = append(css, "fill:"+mermaidColor(fill))
+	} else if color := stringAttribute(a, "color"); len(color) > 0 && hasStyle(a, "filled") {
+		css = append(css, "fill:"+mermaidColor(color))
+	}
+	if color := stringAttribute(a, "color"); len(color) > 0 {
+		css = append(css, "stroke:"+mermaidColor(color))
+	}
+	css = append(css, strokeCSS(a)...)
+	if hasStyle(a, "dashed") {
+		css = append(css, "stroke-dasharray:5 5")
+	} else if hasStyle(a, "dotted") {
+		css = append(css, "stroke-dasharray:2 2")
+	}
+	if color := stringAttribute(a, "fontcolor"); len(color) > 0 {
+		css = append(css, "color:"+mermaidColor(color))
+	}
+	return strings.Join(css, ",")
+}
+
+// strokeCSS returns the stroke width from "penwidth" or the bold style.
+func strokeCSS(a AttributesMap) []string {
+	if width := stringAttribute(a, "penwidth"); len(width) > 0 {
+		return []string{"stroke-width:" + width + "px"}
+	}
+	if hasStyle(a, "bold") {
+		return []string{"stroke-width:2px"}
+	}
+	return nil
+}
+
+// edgeCSS translates the Graphviz styling attributes of an edge into CSS properties for linkStyle.
+// The line style itself (dashed, bold, invisible) is expressed by the link, see mermaidLinkOf.
+func edgeCSS(a AttributesMap) string {
+	css := []string{}
+	if color := stringAttribute(a, "color"); len(color) > 0 {
+		css = append(css, "stroke:"+mermaidColor(color))
+	}
+	if width := stringAttribute(a, "penwidth"); len(width) > 0 {
+		css = append(css, "stroke-width:"+width+"px")
+	}
+	if color := stringAttribute(a, "fontcolor"); len(color) > 0 {
+		css = append(css, "color:"+mermaidColor(color))
+	}
+	return strings.Join(css, ",")
+}
+
+// mermaidLinkOf returns the link that matches the style, arrowhead and dir attributes of an edge.
+func mermaidLinkOf(a AttributesMap, directed bool) string {
+	if hasStyle(a, "invis") {
+		return "~~~"
+	}
+	dir := stringAttribute(a, "dir")
+	head := directed && stringAttribute(a, "arrowhead") != "none" && dir != "none" && dir != "back"
+	tail := directed && dir == "both" && stringAttribute(a, "arrowtail") != "none"
+	var link string
+	switch {
+	case hasStyle(a, "dashed") || hasStyle(a, "dotted"):
+		link = "-.-"
+	case hasStyle(a, "bold"):
+		link = "=="
+		if !head {
+			link = "==="
+		}
+	default:
+		link = "--"
+		if !head {
+			link = "---"
+		}
+	}
+	if head {
+		link += ">"
+	}
+	if tail && head {
+		link = "<" + link
+	}
+	return link
+}
@@ -1,6 +1,7 @@
 package dot
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -10,7 +11,7 @@ func TestMermaidSimple(t *testing.T) {
 	n2 := di.Node("e2").SetAttribute("shape", MermaidShapeRound).SetAttribute("style", "fill:#90EE90")
 	n1.Edge(n2, "what").SetAttribute("x", "y")
 	out := flatten(MermaidGraph(di, MermaidTopDown))
-	if got, want := out, `graph TD;n1("E1");n2("e2");style n2 fill:#90EE90n1-->|"what"|n2;`; got != want {
+	if got, want := out, `graph TD;n1("E1");n2("e2");style n2 fill:#90EE90;n1-->|"what"|n2;`; got != want {
 		t.Errorf("got [%v]:%T want [%v]:%T", got, got, want, want)
 	}
 }
@@ -113,7 +114,39 @@ func TestMermaidSubgraph(t *testing.T) {
 
 	sub3.Node("c1").Edge(sub1.Node("a2"))
 	mf := MermaidFlowchart(di, MermaidLeftToRight)
-	if got, want := flatten(mf), `flowchart LR;n8-->n3;subgraph THREE [three];n8("c1");n9("c2");n8-->n9;end;subgraph one [one];n2("a1");n3("a2");n2-->n3;end;subgraph two [two];n5("b1");n6("b2");n5-->n6;end;`; got != want {
+	if got, want := flatten(mf), `flowchart LR;subgraph THREE ["three"];n8("c1");n9("c2");n8-->n9;end;subgraph one ["one"];n2("a1");n3("a2");n2-->n3;end;subgraph two ["two"];n5("b1");n6("b2");n5-->n6;end;n8-->n3;`; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
+
+func TestMermaidNestedSubgraph(t *testing.T) {
+	di := NewGraph(Directed)
+	outer := di.Subgraph("outer layer").Label(`"outer"`)
+	outer.SetAttribute("direction", "LR")
+	inner := outer.Subgraph("end")
+	deepest := inner.Subgraph("n1")
+	a := outer.Node("a")
+	b := inner.Node("b")
+	c := deepest.Node("c")
+	b.Edge(c)
+	a.Edge(c)
+	di.Node("d").Edge(a)
+	mf := MermaidFlowchart(di, MermaidTopDown)
+	if got, want := flatten(mf), `flowchart TD;n7("d");subgraph outer_layer ["&#34;outer&#34;"];direction LR;n4("a");subgraph sub_end ["end"];n5("b");subgraph sub_n1 ["n1"];n6("c");end;n5-->n6;end;n4-->n6;end;n7-->n4;`; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	// nested scopes are indented
+	if !strings.Contains(mf, "\n\t\t\t\tn6(\"c\");\n") {
+		t.Errorf("expected indented node in:\n%s", mf)
+	}
+}
+
+func TestMermaidUniqueSubgraphIDs(t *testing.T) {
+	di := NewGraph(Directed)
+	di.Subgraph("a b")
+	di.Subgraph("a_b")
+	mf := MermaidFlowchart(di, MermaidTopDown)
+	if got, want := flatten(mf), `flowchart TD;subgraph a_b ["a b"];end;subgraph a_b_2 ["a_b"];end;`; got != want {
 		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
 	}
 }
@@ -140,6 +173,10 @@ func TestLookupShape(t *testing.T) {
 		{"circle", "circle", MermaidShapeCircle, true},
 		{"cylinder", "cylinder", MermaidShapeCylinder, true},
 		{"rhombux", "rhombux", MermaidShapeRhombus, true},
+		{"rhombus", "rhombus", MermaidShapeRhombus, true},
+		{"doc", "doc", MermaidShapeDocument, true},
+		{"db", "db", MermaidShapeCylinder, true},
+		{"doublecircle", "doublecircle", MermaidShapeDoubleCircle, true},
 		{"stadium", "stadium", MermaidShapeStadium, true},
 		{"subroutine", "subroutine", MermaidShapeSubroutine, true},
 		{"trapezoid", "trapezoid", MermaidShapeTrapezoid, true},
@@ -159,3 +196,109 @@ func TestLookupShape(t *testing.T) {
 		})
 	}
 }
+
+func TestMermaidNodeStyling(t *testing.T) {
+	di := NewGraph(Directed)
+	di.Node("a").SetAttribute("fillcolor", "red").SetAttribute("color", "blue").SetAttribute("penwidth", "3")
+	di.Node("b").SetAttribute("style", "filled,dashed").SetAttribute("color", "green").SetAttribute("fontcolor", "white")
+	di.Node("c").SetAttribute("style", "filled,dashed").SetAttribute("color", "green").SetAttribute("fontcolor", "white")
+	di.Node("d").SetAttribute("style", "fill:#90EE90").SetAttribute("color", "black")
+	mf := MermaidFlowchart(di, MermaidTopDown)
+	if got, want := flatten(mf), `flowchart TD;n1("a");n2("b");n3("c");n4("d");style n4 fill:#90EE90,stroke:black;`+
+		`style n1 fill:red,stroke:blue,stroke-width:3px;`+
+		`classDef dotStyle1 fill:green,stroke:green,stroke-dasharray:5 5,color:white;class n2,n3 dotStyle1;`; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
+
+func TestMermaidEdgeStyling(t *testing.T) {
+	di := NewGraph(Directed)
+	a, b := di.Node("a"), di.Node("b")
+	a.Edge(b).Dashed()
+	a.Edge(b).Bold().SetAttribute("color", "red")
+	a.Edge(b).SetAttribute("style", "invis")
+	a.Edge(b).SetAttribute("arrowhead", "none").SetAttribute("color", "red")
+	a.Edge(b).SetAttribute("dir", "both").SetAttribute("penwidth", 2)
+	a.Edge(b, "x").Dotted().SetAttribute("arrowhead", "none")
+	mf := MermaidFlowchart(di, MermaidTopDown)
+	if got, want := flatten(mf), `flowchart TD;n1("a");n2("b");n1-.->n2;n1==>n2;n1~~~n2;n1---n2;n1<-->n2;n1-.-|"x"|n2;`+
+		`linkStyle 1,3 stroke:red;linkStyle 4 stroke-width:2px;`; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
+
+func TestMermaidInvisibleNodes(t *testing.T) {
+	di := NewGraph(Directed)
+	a := di.Node("a").SetAttribute("style", "invis").SetAttribute("fillcolor", "red")
+	b := di.Node("b").SetAttribute("style", "invis")
+	a.Edge(b)
+	mf := MermaidFlowchart(di, MermaidTopDown)
+	if got, want := flatten(mf), `flowchart TD;n1(" ");n2(" ");n1-->n2;classDef dotStyle1 fill:none,stroke:none;class n1,n2 dotStyle1;`; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
+
+func TestMermaidUndirectedEdgeStyling(t *testing.T) {
+	un := NewGraph(Undirected)
+	un.Node("a").Edge(un.Node("b")).Bold()
+	if got, want := flatten(MermaidGraph(un, MermaidTopDown)), `graph TD;n1("a");n2("b");n1===n2;`; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
+
+func TestMermaidExtendedShapes(t *testing.T) {
+	di := NewGraph(Directed)
+	di.Node("doc").SetAttribute("shape", MermaidShapeDocument)
+	di.Node("db").SetAttribute("shape", "database")
+	di.Node("plain")
+	mf := MermaidFlowchartWithOptions(di, MermaidOptions{Orientation: MermaidLeftToRight, ExtendedShapes: true})
+	if got, want := flatten(mf), `flowchart LR;n2@{ shape: cyl, label: "db" };n1@{ shape: doc, label: "doc" };n3@{ shape: rounded, label: "plain" };`; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	// classic brackets without the option
+	if got, want := flatten(MermaidFlowchart(di, MermaidLeftToRight)), `flowchart LR;n2[("db")];n1["doc"];n3("plain");`; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
+
+func TestMermaidInteractive(t *testing.T) {
+	di := NewGraph(Directed)
+	di.Node("a").SetAttribute("href", "https://example.com").SetAttribute("tooltip", `say "hi"`)
+	di.Node("b").SetAttribute("URL", "https://example.org").SetAttribute("target", "_blank")
+	di.Node("c").SetAttribute("tooltip", "no link")
+	mf := MermaidFlowchartWithOptions(di, MermaidOptions{Interactive: true})
+	if got, want := flatten(mf), `flowchart TD;n1("a");click n1 href "https://example.com" "say #quot;hi#quot;";n2("b");click n2 href "https://example.org" _blank;n3("c");`; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if strings.Contains(MermaidFlowchart(di, MermaidTopDown), "click") {
+		t.Error("click without Interactive option")
+	}
+}
+
+func TestMermaidMarkdownLabels(t *testing.T) {
+	di := NewGraph(Directed)
+	sub := di.Subgraph("s").Label("**sub**")
+	sub.Node("a").Label("*a* & <b>").Edge(di.Node("b").Label("say \"`hi`\""), "_go_")
+	mf := MermaidFlowchartWithOptions(di, MermaidOptions{MarkdownLabels: true})
+	if got, want := flatten(mf), "flowchart TD;n3(\"`say #quot;#96;hi#96;#quot;`\");subgraph s [\"`**sub**`\"];n2(\"`*a* & <b>`\");end;n2-->|\"`_go_`\"|n3;"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
+
+func TestMermaidRecordAsLines(t *testing.T) {
+	di := NewGraph(Directed)
+	rb := di.Node("r").NewRecordBuilder()
+	rb.FieldWithId("id <pk>", "id")
+	rb.Nesting(func() {
+		rb.Field(`name\lemail\\ok`)
+	})
+	rb.Build()
+	di.Node("m").SetAttribute("shape", "Mrecord").Label("<a> a|")
+	if got, want := flatten(MermaidFlowchart(di, MermaidTopDown)), `flowchart TD;n2("a");n1["id &lt;pk&gt;<br>name<br>email\ok"];`; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	md := MermaidFlowchartWithOptions(di, MermaidOptions{MarkdownLabels: true})
+	if got, want := md, "\tn1[\"`id \\<pk\\>\nname\nemail\\\\ok`\"];\n"; !strings.Contains(got, want) {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
@@ -0,0 +1,265 @@
+package dot
+
+import "sort"
+
+// DeleteEdge removes the edge from the graph (or the subgraph in which it is stored).
+// Returns false if the edge wasn't found, true otherwise.
+func (g *Graph) DeleteEdge(e Edge) bool {
+	root := g.Root()
+	for _, each := range root.outIndex[e.from.seq] {
+		if isSameEdge(each, e) {
+			root.unstoreEdge(each)
+			return true
+		}
+	}
+	return false
+}
+
+// isSameEdge returns whether both values represent the same created edge of the same root graph.
+// The copies made by DeepCopy have the seq of the original edges.
+func isSameEdge(one, two Edge) bool {
+	return one.seq == two.seq && one.graph.Root() == two.graph.Root()
+}
+
+// stored returns the node as it is now stored in this root graph.
+// Returns false if the node was deleted or belongs to another graph.
+func (g *Graph) stored(n Node) (Node, bool) {
+	n = n.current()
+	if n.graph == nil || n.graph.Root() != g {
+		return n, false
+	}
+	each, ok := n.graph.nodes[n.id]
+	return each, ok && each.seq == n.seq
+}
+
+// RenameNode changes the id of a node and updates all its edges and rank groups.
+// If the label of the node equals the old id then the label is changed too.
+// Returns the updated Node and true ; false if the node wasn't found or the new id is already in use.
+// Node and Edge values obtained before the rename still return the old id ; such Node values can still be used
+// to create edges.
+func (g *Graph) RenameNode(n Node, newID string) (Node, bool) {
+	root := g.Root()
+	current, ok := root.stored(n)
+	if !ok {
+		return n, false
+	}
+	if _, exists := root.FindNodeById(newID); exists {
+		return n, false
+	}
+	oldID := current.id
+	if current.Value("label") == oldID {
+		current.SetAttribute("label", newID)
+	}
+	renamed := current
+	renamed.id = newID
+	delete(current.graph.nodes, oldID)
+	current.graph.nodes[newID] = renamed
+	root.relocated[renamed.seq] = renamed
+	root.replaceNode(renamed)
+	return renamed, true
+}
+
+// MoveNode moves a node into another (sub)graph of the same root graph.
+// Edges are stored again in the innermost subgraph that contains both nodes.
+// The node is removed from rank groups of subgraphs that no longer contain it.
+// Returns the updated Node ; Node values obtained before the move refer to the target too.
+// Panics if the target belongs to another graph.
+func (g *Graph) MoveNode(n Node, target *Graph) Node {
+	root := g.Root()
+	if target.Root() != root {
+		panic("cannot move a node into a subgraph of another graph")
+	}
+	current, ok := root.stored(n)
+	if !ok {
+		return n
+	}
+	moved := current
+	moved.graph = target
+	delete(current.graph.nodes, current.id)
+	target.nodes[current.id] = moved
+	root.relocated[moved.seq] = moved
+	root.replaceNode(moved)
+	// rank groups of a subgraph can only have nodes of that subgraph (or deeper)
+	root.visitScopes(func(each *Graph) {
+		if commonParentOf(each, target) != each {
+			each.removeFromRanks(moved.seq)
+		}
+	})
+	return moved
+}
+
+// DeleteSubgraph removes the subgraph, including its nodes, their edges and all its subgraphs.
+// To keep nodes, use MoveNode before deleting.
+// Returns false if the subgraph is the root or not part of this graph, true otherwise.
+func (g *Graph) DeleteSubgraph(sub *Graph) bool {
+	if sub.parent == nil || sub.Root() != g.Root() {
+		return false
+	}
+	deleted := []Node{}
+	sub.VisitNodes(func(n Node) bool {
+		deleted = append(deleted, n)
+		return false
+	})
+	root := g.Root()
+	for _, n := range deleted {
+		root.updateEdgesOf(n, func(e Edge) (Edge, bool) {
+			return e, false
+		})
+	}
+	for key, each := range sub.parent.subgraphs {
+		if each == sub {
+			delete(sub.parent.subgraphs, key)
+		}
+	}
+	root.visitScopes(func(each *Graph) {
+		for _, n := range deleted {
+			each.removeFromRanks(n.seq)
+		}
+	})
+	sub.parent = nil
+	return true
+}
+
+// MergeNodes merges node b into node a and deletes b.
+// All edges of b are connected to a instead ; edges between a and b are removed.
+// Attributes of b that are not set on a are copied. Rank groups with b will have a instead,
+// unless a is not part of the (sub)graph of the rank group. Node values of b refer to a after the merge.
+// Returns the updated node a.
+func (g *Graph) MergeNodes(a, b Node) Node {
+	root := g.Root()
+	a, okA := root.stored(a)
+	b, okB := root.stored(b)
+	if !okA || !okB || a.seq == b.seq {
+		return a
+	}
+	for k, v := range b.attributes {
+		if _, ok := a.attributes[k]; !ok {
+			a.attributes[k] = v
+		}
+	}
+	delete(b.graph.nodes, b.id)
+	root.relocated[b.seq] = a
+	root.updateEdgesOf(b, func(e Edge) (Edge, bool) {
+		between := e.from.seq == a.seq || e.to.seq == a.seq
+		if e.from.seq == b.seq {
+			e.from = a
+		}
+		if e.to.seq == b.seq {
+			e.to = a
+		}
+		return e, !between
+	})
+	root.visitScopes(func(each *Graph) {
+		// rank groups of a subgraph can only have nodes of that subgraph (or deeper)
+		if commonParentOf(each, a.graph) != each {
+			each.removeFromRanks(b.seq)
+			return
+		}
+		for _, groups := range each.rankGroups() {
+			for key, nodes := range groups {
+				merged := nodes[:0]
+				hasA := false
+				for _, n := range nodes {
+					if n.seq == a.seq || n.seq == b.seq {
+						if hasA {
+							continue
+						}
+						hasA = true
+						n = a
+					}
+					merged = append(merged, n)
+				}
+				groups[key] = merged
+			}
+		}
+	})
+	return a
+}
+
+// rewriteEdges calls the function for each edge in the (root) graph and all its subgraphs and rebuilds the index.
+// Edges for which the function returns false are removed ; the others are stored
+// in the innermost subgraph that contains both (possibly changed) nodes.
+// To change the edges of a single node, use updateEdgesOf.
+func (g *Graph) rewriteEdges(f func(e Edge) (Edge, bool)) {
+	kept := []Edge{}
+	var collect func(each *Graph)
+	collect = func(each *Graph) {
+		for _, key := range each.sortedEdgesFromKeys() {
+			for _, e := range each.edgesFrom[key] {
+				if changed, keep := f(e); keep {
+					kept = append(kept, changed)
+				}
+			}
+		}
+		each.edgesFrom = map[string][]Edge{}
+		for _, key := range each.sortedSubgraphsKeys() {
+			collect(each.subgraphs[key])
+		}
+	}
+	collect(g)
+	sort.SliceStable(kept, func(i, j int) bool { return kept[i].seq < kept[j].seq })
+	g.outIndex = map[int][]Edge{}
+	g.inIndex = map[int][]Edge{}
+	for _, e := range kept {
+		g.storeEdge(e)
+	}
+}
+
+// replaceNode updates all edges and rank groups that refer to the node with the same seq.
+func (g *Graph) replaceNode(n Node) {
+	g.updateEdgesOf(n, func(e Edge) (Edge, bool) {
+		if e.from.seq == n.seq {
+			e.from = n
+		}
+		if e.to.seq == n.seq {
+			e.to = n
+		}
+		return e, true
+	})
+	g.visitScopes(func(each *Graph) {
+		for _, groups := range each.rankGroups() {
+			for _, nodes := range groups {
+				for i, other := range nodes {
+					if other.seq == n.seq {
+						nodes[i] = n
+					}
+				}
+			}
+		}
+	})
+}
+
+// Ranks returns the nodes of the rank groups of this (sub)graph by rank: "same", "min", "source", "max" or "sink".
+func (g *Graph) Ranks() map[string][]Node {
+	ranks := map[string][]Node{}
+	for _, groups := range g.rankGroups() {
+		for rank, nodes := range groups {
+			ranks[rank] = append([]Node{}, nodes...)
+		}
+	}
+	return ranks
+}
+
+// rankGroups returns the maps of all rank groups of this (sub)graph.
+func (g *Graph) rankGroups() []map[string][]Node {
+	return []map[string][]Node{g.sameRanks, g.minRanks, g.sourceRanks, g.maxRanks, g.sinkRanks}
+}
+
+// removeFromRanks removes the node with the seq from all rank groups of this (sub)graph.
+func (g *Graph) removeFromRanks(seq int) {
+	for _, groups := range g.rankGroups() {
+		for key, nodes := range groups {
+			kept := nodes[:0]
+			for _, n := range nodes {
+				if n.seq != seq {
+					kept = append(kept, n)
+				}
+			}
+			if len(kept) == 0 {
+				delete(groups, key)
+			} else {
+				groups[key] = kept
+			}
+		}
+	}
+}
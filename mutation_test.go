@@ -0,0 +1,216 @@
+package dot
+
+import "testing"
+
+func TestDeleteEdge(t *testing.T) {
+	di := NewGraph(Directed)
+	a, b := di.Node("a"), di.Node("b")
+	a.Edge(b, "first")
+	second := a.Edge(b, "second")
+	if !di.DeleteEdge(second) {
+		t.Fatal("expected deleted")
+	}
+	if di.DeleteEdge(second) {
+		t.Error("expected not found")
+	}
+	if got, want := flatten(di.String()), `digraph  {n1[label="a"];n2[label="b"];n1->n2[label="first"];}`; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestDeleteEdgeInSubgraph(t *testing.T) {
+	di := NewGraph(Directed)
+	sub := di.Subgraph("sub")
+	e := sub.Node("a").Edge(sub.Node("b"))
+	if !di.DeleteEdge(e) {
+		t.Fatal("expected deleted")
+	}
+	if got, want := len(sub.FindEdges(e.From(), e.To())), 0; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestDeleteEdgeOfCopy(t *testing.T) {
+	di := NewGraph(Directed)
+	e := di.Node("a").Edge(di.Node("b"))
+	copied := di.DeepCopy()
+	if copied.DeleteEdge(e) {
+		t.Error("expected edge of other graph not found")
+	}
+	a, _ := copied.FindNodeById("a")
+	if !copied.DeleteEdge(a.OutEdges()[0]) {
+		t.Fatal("expected deleted")
+	}
+	if got, want := len(di.FindEdges(e.From(), e.To())), 1; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestDeleteNodeFromRanks(t *testing.T) {
+	di := NewGraph(Directed)
+	a, b := di.Node("a"), di.Node("b")
+	di.AddToSameRank(a, b)
+	di.AddToMinRank(b)
+	di.DeleteNode("b")
+	if got, want := flatten(di.String()), `digraph  {n1[label="a"];{rank=same; n1;};}`; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestRenameNode(t *testing.T) {
+	di := NewGraph(Directed, NodeIDOption{})
+	a, b := di.Node("a"), di.Node("b").Label("B")
+	a.Edge(b)
+	b.Edge(a)
+	di.AddToSameRank(a, b)
+	renamed, ok := di.RenameNode(a, "z")
+	if !ok {
+		t.Fatal("expected renamed")
+	}
+	if got, want := renamed.ID(), "z"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if _, ok := di.RenameNode(renamed, "b"); ok {
+		t.Error("expected rename to existing id to fail")
+	}
+	if got, want := flatten(di.String()), `digraph  {b[label="B"];z[label="z"];b->z;z->b;{rank=same; z;b;};}`; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestMoveNode(t *testing.T) {
+	di := NewGraph(Directed)
+	sub := di.Subgraph("sub")
+	a := di.Node("a")
+	b := sub.Node("b")
+	c := di.Node("c")
+	a.Edge(c)
+	b.Edge(c)
+	di.AddToSameRank(a, c)
+	moved := di.MoveNode(a, sub)
+	if got, want := moved.Graph(), sub; got != want {
+		t.Errorf("got [%v] want [%v]", got.ID(), want.ID())
+	}
+	di.MoveNode(c, sub)
+	// all edges are now inside the subgraph, the root rank group is kept
+	if got, want := flatten(di.String()), `digraph  {subgraph s1 {label="sub";n2[label="a"];n3[label="b"];n4[label="c"];n2->n4;n3->n4;}{rank=same; n2;n4;};}`; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	// moving out of the subgraph removes it from the rank group of the subgraph
+	sub.AddToSameRank(moved)
+	di.MoveNode(moved, di)
+	if got, want := len(sub.sameRanks), 0; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestMoveNodeStaleValues(t *testing.T) {
+	di := NewGraph(Directed)
+	sub := di.Subgraph("sub")
+	a, b := di.Node("a"), sub.Node("b")
+	a.Edge(b)
+	di.MoveNode(a, sub)
+	if got, want := a.Graph(), sub; got != want {
+		t.Errorf("got [%v] want [%v]", got.ID(), want.ID())
+	}
+	if got, want := a.OutEdges()[0].From().Graph(), sub; got != want {
+		t.Errorf("got [%v] want [%v]", got.ID(), want.ID())
+	}
+	// the new edge is stored in the subgraph, not the root
+	a.Edge(b, "new")
+	if got, want := len(sub.FindEdges(a, b)), 2; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := len(di.edgesFrom), 0; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestMoveNodeOtherGraph(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic")
+		}
+	}()
+	one, two := NewGraph(Directed), NewGraph(Directed)
+	one.MoveNode(one.Node("a"), two)
+}
+
+func TestDeleteSubgraph(t *testing.T) {
+	di := NewGraph(Directed)
+	sub := di.Subgraph("sub")
+	inner := sub.Subgraph("inner")
+	a := di.Node("a")
+	b := sub.Node("b")
+	c := inner.Node("c")
+	a.Edge(b)
+	a.Edge(c)
+	di.AddToSameRank(a, c)
+	if !di.DeleteSubgraph(sub) {
+		t.Fatal("expected deleted")
+	}
+	if di.DeleteSubgraph(di) {
+		t.Error("cannot delete root")
+	}
+	if got, want := flatten(di.String()), `digraph  {n3[label="a"];{rank=same; n3;};}`; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestMergeNodes(t *testing.T) {
+	di := NewGraph(Directed)
+	sub := di.Subgraph("sub")
+	a := di.Node("a")
+	b := sub.Node("b").SetAttribute("color", "red")
+	c := di.Node("c")
+	a.Edge(b)
+	b.Edge(c, "bc")
+	c.Edge(b)
+	di.AddToSameRank(a, b, c)
+	merged := di.MergeNodes(a, b)
+	if got, want := merged.Value("color"), "red"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if _, ok := di.FindNodeById("b"); ok {
+		t.Error("b not deleted")
+	}
+	if got, want := flatten(di.String()), `digraph  {subgraph s1 {label="sub";}n2[color="red",label="a"];n4[label="c"];n2->n4[label="bc"];n4->n2;{rank=same; n2;n4;};}`; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestMergeNodesRanksOfSubgraph(t *testing.T) {
+	di := NewGraph(Directed)
+	sub := di.Subgraph("sub")
+	a := di.Node("a")
+	b, c := sub.Node("b"), sub.Node("c")
+	sub.AddToSameRank(b, c)
+	di.AddToMinRank(b)
+	merged := di.MergeNodes(a, b)
+	// a is not part of the subgraph so it does not replace b in its rank group
+	if got, want := len(sub.sameRanks["same"]), 1; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := di.minRanks["min"][0].seq, merged.seq; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	// values of b refer to a
+	b.Edge(c)
+	if got, want := len(a.OutEdges()), 1; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestRanks(t *testing.T) {
+	g := NewGraph(Directed)
+	a, b := g.Node("a"), g.Node("b")
+	g.AddToSameRank(a, b)
+	g.AddToSinkRank(b)
+	ranks := g.Ranks()
+	if got, want := len(ranks["same"])+len(ranks["sink"]), 3; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if _, ok := ranks["min"]; ok {
+		t.Error("expected no min rank")
+	}
+}
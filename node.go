@@ -11,6 +11,10 @@ type Node struct {
 // ID returns the assigned id to this node.
 func (n Node) ID() string { return n.id }
 
+// Seq returns the sequence number of the node, which identifies it in its root graph
+// even if nodes of different subgraphs have the same id.
+func (n Node) Seq() int { return n.seq }
+
 // SetAttribute sets label=value and return the Node
 func (n Node) SetAttribute(label string, value interface{}) Node {
 	n.AttributesMap.SetAttribute(label, value)
@@ -32,6 +36,11 @@ func (n Node) Edge(toNode Node, labels ...string) Edge {
 	return n.graph.Edge(n, toNode, labels...)
 }
 
+// PortEdge creates a new edge from a port of this node to a port of another node ; see Graph.PortEdge.
+func (n Node) PortEdge(fromPort Port, toNode Node, toPort Port, labels ...string) Edge {
+	return n.graph.PortEdge(n, fromPort, toNode, toPort, labels...)
+}
+
 // EdgesTo returns all existing edges between this Node and the argument Node.
 func (n Node) EdgesTo(toNode Node) []Edge {
 	return n.graph.FindEdges(n, toNode)
@@ -60,6 +69,25 @@ func (n Node) NewRecordBuilder() *recordBuilder {
 	return newRecordBuilder(n)
 }
 
+// Graph returns the (sub)graph that contains the node, also after it was moved by Graph.MoveNode.
 func (n Node) Graph() *Graph {
-	return n.graph
+	return n.current().graph
+}
+
+// current returns the node as it is now stored if it was moved, renamed or merged since this value was obtained.
+func (n Node) current() Node {
+	if n.graph == nil {
+		return n
+	}
+	relocated := n.graph.Root().relocated
+	for {
+		next, ok := relocated[n.seq]
+		if !ok {
+			return n
+		}
+		if next.seq == n.seq {
+			return next
+		}
+		n = next
+	}
 }
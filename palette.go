@@ -0,0 +1,113 @@
+package dot
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Palette is a list of distinct colors for categories, such as the subgraphs of nodes.
+type Palette []Color
+
+// Categorical palettes from the Brewer qualitative schemes, as "#RRGGBB" colors.
+var (
+	PaletteAccent  = brewerPalette("accent")
+	PaletteDark2   = brewerPalette("dark2")
+	PalettePaired  = brewerPalette("paired")
+	PalettePastel1 = brewerPalette("pastel1")
+	PalettePastel2 = brewerPalette("pastel2")
+	PaletteSet1    = brewerPalette("set1")
+	PaletteSet2    = brewerPalette("set2")
+	PaletteSet3    = brewerPalette("set3")
+)
+
+func brewerPalette(family string) Palette {
+	p := Palette{}
+	for _, v := range brewerFamilies[family].colors {
+		p = append(p, RGB(uint8(v>>16), uint8(v>>8), uint8(v)))
+	}
+	return p
+}
+
+// Color returns the color at the index ; colors are repeated if the index exceeds the palette.
+func (p Palette) Color(index int) Color {
+	if len(p) == 0 {
+		return Black
+	}
+	return p[index%len(p)]
+}
+
+// GroupKey returns the category of a node for assigning colors ; nodes with an empty key are not colored.
+type GroupKey func(n Node) string
+
+// GroupBySubgraph is a GroupKey that returns the keys of the subgraph of the node and its parents, joined by "/".
+// Nodes of the root graph have no group.
+func GroupBySubgraph(n Node) string {
+	keys := []string{}
+	for each := n.Graph(); each.Parent() != nil; each = each.Parent() {
+		keys = append([]string{each.Key()}, keys...)
+	}
+	return strings.Join(keys, "/")
+}
+
+// GroupByAttribute returns a GroupKey that returns the value of the attribute of the node ; empty if absent.
+func GroupByAttribute(name string) GroupKey {
+	return func(n Node) string {
+		if v := n.Attribute(name); v != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return ""
+	}
+}
+
+// ColorNodes sets the attributes (fillcolor if none are given) of all nodes of the graph and its subgraphs
+// to the palette color of their group. Groups are assigned colors in sorted order.
+// Setting fillcolor also adds "filled" to the style of the node, unless its style or the defaults have it.
+// Returns the colors by group.
+func (p Palette) ColorNodes(g *Graph, group GroupKey, attributes ...string) map[string]Color {
+	if len(attributes) == 0 {
+		attributes = []string{"fillcolor"}
+	}
+	nodes := g.FindNodes()
+	keys := []string{}
+	colors := map[string]Color{}
+	for _, each := range nodes {
+		key := group(each)
+		if _, ok := colors[key]; !ok && len(key) > 0 {
+			colors[key] = ""
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for i, each := range keys {
+		colors[each] = p.Color(i)
+	}
+	for _, each := range nodes {
+		c, ok := colors[group(each)]
+		if !ok {
+			continue
+		}
+		for _, name := range attributes {
+			each.SetAttribute(name, string(c))
+			if name == "fillcolor" {
+				addFilledStyle(each)
+			}
+		}
+	}
+	return colors
+}
+
+// addFilledStyle adds "filled" to the effective style of the node (including defaults and classes) if it does not have it.
+func addFilledStyle(n Node) {
+	style, _ := n.EffectiveAttributes()["style"].(string)
+	for _, each := range strings.Split(style, ",") {
+		if strings.TrimSpace(each) == string(StyleFilled) {
+			return
+		}
+	}
+	if len(style) > 0 {
+		n.SetAttribute("style", style+","+string(StyleFilled))
+		return
+	}
+	n.SetAttribute("style", string(StyleFilled))
+}
@@ -0,0 +1,45 @@
+package dot
+
+import "testing"
+
+func TestPaletteColorNodesBySubgraph(t *testing.T) {
+	g := NewGraph(Directed)
+	g.Node("root")
+	g.Subgraph("b").Node("b1")
+	g.Subgraph("a").Node("a1").SetAttribute("style", "rounded")
+	g.Subgraph("a").Subgraph("inner").Node("i1")
+	colors := PaletteSet1.ColorNodes(g, GroupBySubgraph)
+	if got, want := len(colors), 3; got != want {
+		t.Fatalf("got [%v] want [%v]", got, want)
+	}
+	if got, want := colors["a/inner"], PaletteSet1[1]; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	a1, _ := g.FindNodeById("a1")
+	if got, want := a1.Attribute("fillcolor"), "#e41a1c"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := a1.Attribute("style"), "rounded,filled"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	root, _ := g.FindNodeById("root")
+	if got := root.Attribute("fillcolor"); got != nil {
+		t.Errorf("got [%v] want no color", got)
+	}
+}
+
+func TestPaletteColorNodesByAttribute(t *testing.T) {
+	g := NewGraph(Directed)
+	g.NodeDefaults().SetAttribute("style", "filled")
+	g.Node("a").SetAttribute("team", "x")
+	g.Node("b").SetAttribute("team", "y")
+	g.Node("c").SetAttribute("team", "x")
+	small := Palette{"red", "blue"}
+	small.ColorNodes(g, GroupByAttribute("team"), "color", "fillcolor")
+	if got, want := flatten(g.String()), `digraph  {node[style="filled"];n1[color="red",fillcolor="red",label="a",team="x"];n2[color="blue",fillcolor="blue",label="b",team="y"];n3[color="red",fillcolor="red",label="c",team="x"];}`; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := small.Color(3), Color("blue"); got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
@@ -0,0 +1,402 @@
+package dot
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Parse reads a graph written in the DOT language and returns it as a Graph.
+// It supports the full DOT grammar: strict, graph and digraph headers, node, edge
+// and graph attribute statements, nested subgraphs and clusters, edge chains,
+// subgraphs as edge operands, ports with compass points, HTML strings and quoted IDs.
+//
+// Nodes belong to the deepest subgraph (along one nesting path) in which they are mentioned.
+// Anonymous subgraphs that only set a rank are stored as rank groups (see AddToSameRank).
+// Default attributes (node [..] and edge [..]) are copied onto the elements created after them.
+// Quoted values that use escape sequences other than \" are kept as a Literal.
+// The returned Graph has the NodeIDOption such that writing it keeps the original identifiers.
+// Errors are of type *ParseError and carry the line and column of the problem.
+func Parse(r io.Reader) (*Graph, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{scanner: newScanner(string(data))}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	ast, err := p.parseGraph()
+	if err != nil {
+		return nil, err
+	}
+	return newGraphBuilder(ast).build()
+}
+
+// ParseString is a convenient Parse for DOT source held in a string.
+func ParseString(src string) (*Graph, error) {
+	return Parse(strings.NewReader(src))
+}
+
+// syntax tree
+
+type astGraph struct {
+	strict   bool
+	directed bool
+	id       string
+	stmts    []astStmt
+}
+
+type astStmt interface{}
+
+// astAttrStmt is one of: graph [..], node [..] or edge [..]
+type astAttrStmt struct {
+	kind  string
+	attrs []astAttr
+}
+
+// astAssign is a graph attribute assignment: ID = ID
+type astAssign struct {
+	attr astAttr
+}
+
+type astNodeStmt struct {
+	node  astNodeID
+	attrs []astAttr
+}
+
+// astEdgeStmt operands are astNodeID or *astSubgraph values.
+type astEdgeStmt struct {
+	operands []interface{}
+	ops      []token
+	attrs    []astAttr
+}
+
+type astSubgraph struct {
+	id    string
+	stmts []astStmt
+	line  int
+}
+
+type astNodeID struct {
+	id           string
+	port         string
+	line, column int
+}
+
+type astAttr struct {
+	key   string
+	value interface{}
+}
+
+// parser is a recursive descent parser for the DOT grammar.
+type parser struct {
+	scanner *scanner
+	tok     token
+}
+
+func (p *parser) advance() error {
+	tok, err := p.scanner.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return &ParseError{Line: p.tok.line, Column: p.tok.column, Message: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) unexpected(expected string) error {
+	return p.errorf("unexpected %v, expected %s", p.tok, expected)
+}
+
+// isKeyword returns whether the current token is the (case insensitive) keyword.
+func (p *parser) isKeyword(keyword string) bool {
+	return p.tok.kind == tokenID && !p.tok.quoted && strings.EqualFold(p.tok.text, keyword)
+}
+
+func (p *parser) expect(kind tokenKind) error {
+	if p.tok.kind != kind {
+		return p.unexpected(kind.String())
+	}
+	return p.advance()
+}
+
+// graph : [ strict ] (graph | digraph) [ ID ] '{' stmt_list '}'
+func (p *parser) parseGraph() (*astGraph, error) {
+	g := new(astGraph)
+	if p.isKeyword("strict") {
+		g.strict = true
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	switch {
+	case p.isKeyword("graph"):
+	case p.isKeyword("digraph"):
+		g.directed = true
+	default:
+		return nil, p.unexpected("graph or digraph")
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind == tokenID {
+		g.id = p.tok.text
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	stmts, err := p.parseBlock()
+	if err != nil {
+		return nil, err
+	}
+	g.stmts = stmts
+	if p.tok.kind != tokenEOF {
+		return nil, p.unexpected("end of input")
+	}
+	if err := p.checkEdgeOps(g.stmts, g.directed); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// checkEdgeOps verifies that edges use -> in a digraph and -- in a graph.
+func (p *parser) checkEdgeOps(stmts []astStmt, directed bool) error {
+	want := tokenUndirectedEdge
+	if directed {
+		want = tokenDirectedEdge
+	}
+	for _, each := range stmts {
+		switch stmt := each.(type) {
+		case *astEdgeStmt:
+			for _, op := range stmt.ops {
+				if op.kind != want {
+					return &ParseError{Line: op.line, Column: op.column,
+						Message: fmt.Sprintf("unexpected %v, expected %v", op.kind, want)}
+				}
+			}
+			for _, operand := range stmt.operands {
+				if sub, ok := operand.(*astSubgraph); ok {
+					if err := p.checkEdgeOps(sub.stmts, directed); err != nil {
+						return err
+					}
+				}
+			}
+		case *astSubgraph:
+			if err := p.checkEdgeOps(stmt.stmts, directed); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// parseBlock parses '{' stmt_list '}'
+func (p *parser) parseBlock() ([]astStmt, error) {
+	if err := p.expect(tokenLeftBrace); err != nil {
+		return nil, err
+	}
+	stmts := []astStmt{}
+	for p.tok.kind != tokenRightBrace {
+		if p.tok.kind == tokenEOF {
+			return nil, p.unexpected("'}'")
+		}
+		stmt, err := p.parseStmt()
+		if err != nil {
+			return nil, err
+		}
+		stmts = append(stmts, stmt)
+		if p.tok.kind == tokenSemicolon {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return stmts, p.advance()
+}
+
+// stmt : node_stmt | edge_stmt | attr_stmt | ID '=' ID | subgraph
+func (p *parser) parseStmt() (astStmt, error) {
+	if p.isKeyword("graph") || p.isKeyword("node") || p.isKeyword("edge") {
+		kind := strings.ToLower(p.tok.text)
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if p.tok.kind != tokenLeftBracket {
+			return nil, p.unexpected("'['")
+		}
+		attrs, err := p.parseAttrList()
+		if err != nil {
+			return nil, err
+		}
+		return &astAttrStmt{kind: kind, attrs: attrs}, nil
+	}
+	if p.isKeyword("subgraph") || p.tok.kind == tokenLeftBrace {
+		sub, err := p.parseSubgraph()
+		if err != nil {
+			return nil, err
+		}
+		return p.parseEdgeRest(sub)
+	}
+	if p.tok.kind != tokenID {
+		return nil, p.unexpected("statement")
+	}
+	id := p.tok
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind == tokenEqual {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if p.tok.kind != tokenID {
+			return nil, p.unexpected("identifier")
+		}
+		value := p.tok.value
+		return &astAssign{attr: astAttr{key: id.text, value: value}}, p.advance()
+	}
+	node, err := p.parsePort(id)
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind == tokenDirectedEdge || p.tok.kind == tokenUndirectedEdge {
+		return p.parseEdgeRest(node)
+	}
+	stmt := &astNodeStmt{node: node}
+	if p.tok.kind == tokenLeftBracket {
+		attrs, err := p.parseAttrList()
+		if err != nil {
+			return nil, err
+		}
+		stmt.attrs = attrs
+	}
+	return stmt, nil
+}
+
+// parsePort parses the optional port of a node_id whose ID is already consumed.
+// port : ':' ID [ ':' compass_pt ]
+func (p *parser) parsePort(id token) (astNodeID, error) {
+	node := astNodeID{id: id.text, line: id.line, column: id.column}
+	parts := []string{}
+	for p.tok.kind == tokenColon && len(parts) < 2 {
+		if err := p.advance(); err != nil {
+			return node, err
+		}
+		if p.tok.kind != tokenID {
+			return node, p.unexpected("port")
+		}
+		parts = append(parts, p.tok.text)
+		if err := p.advance(); err != nil {
+			return node, err
+		}
+	}
+	node.port = strings.Join(parts, ":")
+	return node, nil
+}
+
+// parseEdgeRest parses edgeRHS [ attr_list ] if present after the first operand.
+// A subgraph without edgeRHS is returned as is.
+func (p *parser) parseEdgeRest(first interface{}) (astStmt, error) {
+	if p.tok.kind != tokenDirectedEdge && p.tok.kind != tokenUndirectedEdge {
+		return first, nil
+	}
+	stmt := &astEdgeStmt{operands: []interface{}{first}}
+	for p.tok.kind == tokenDirectedEdge || p.tok.kind == tokenUndirectedEdge {
+		stmt.ops = append(stmt.ops, p.tok)
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		switch {
+		case p.isKeyword("subgraph") || p.tok.kind == tokenLeftBrace:
+			sub, err := p.parseSubgraph()
+			if err != nil {
+				return nil, err
+			}
+			stmt.operands = append(stmt.operands, sub)
+		case p.tok.kind == tokenID:
+			id := p.tok
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			node, err := p.parsePort(id)
+			if err != nil {
+				return nil, err
+			}
+			stmt.operands = append(stmt.operands, node)
+		default:
+			return nil, p.unexpected("node or subgraph")
+		}
+	}
+	if p.tok.kind == tokenLeftBracket {
+		attrs, err := p.parseAttrList()
+		if err != nil {
+			return nil, err
+		}
+		stmt.attrs = attrs
+	}
+	return stmt, nil
+}
+
+// subgraph : [ subgraph [ ID ] ] '{' stmt_list '}'
+func (p *parser) parseSubgraph() (*astSubgraph, error) {
+	sub := &astSubgraph{line: p.tok.line}
+	if p.isKeyword("subgraph") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if p.tok.kind == tokenID {
+			sub.id = p.tok.text
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	stmts, err := p.parseBlock()
+	if err != nil {
+		return nil, err
+	}
+	sub.stmts = stmts
+	return sub, nil
+}
+
+// attr_list : '[' [ a_list ] ']' [ attr_list ]
+// a_list : ID '=' ID [ (';' | ',') ] [ a_list ]
+func (p *parser) parseAttrList() ([]astAttr, error) {
+	attrs := []astAttr{}
+	for p.tok.kind == tokenLeftBracket {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		for p.tok.kind != tokenRightBracket {
+			if p.tok.kind != tokenID {
+				return nil, p.unexpected("attribute name")
+			}
+			key := p.tok.text
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if err := p.expect(tokenEqual); err != nil {
+				return nil, err
+			}
+			if p.tok.kind != tokenID {
+				return nil, p.unexpected("attribute value")
+			}
+			attrs = append(attrs, astAttr{key: key, value: p.tok.value})
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if p.tok.kind == tokenSemicolon || p.tok.kind == tokenComma {
+				if err := p.advance(); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	return attrs, nil
+}
@@ -0,0 +1,353 @@
+package dot
+
+import "fmt"
+
+type scopeKind int
+
+const (
+	// scopeGraph is a subgraph (or the root) that becomes a Graph.
+	scopeGraph scopeKind = iota
+	// scopeTransparent is an anonymous subgraph without graph attributes ; it only scopes defaults.
+	scopeTransparent
+	// scopeRank is an anonymous subgraph that only lists nodes for a rank group.
+	scopeRank
+)
+
+// parseScope is the static information of a (sub)graph in the syntax tree.
+type parseScope struct {
+	kind scopeKind
+	name string
+	// owner is the nearest scope (self included) of kind scopeGraph.
+	owner *parseScope
+	// graphParent is the owner of the enclosing scope ; nil for the root.
+	graphParent *parseScope
+	graph       *Graph
+	rank        string
+}
+
+// isBelow returns whether other is a strict ancestor of this (graph) scope.
+func (s *parseScope) isBelow(other *parseScope) bool {
+	for each := s.graphParent; each != nil; each = each.graphParent {
+		if each == other {
+			return true
+		}
+	}
+	return false
+}
+
+// parseEnv holds the defaults in effect while building a scope.
+type parseEnv struct {
+	scope        *parseScope
+	nodeDefaults map[string]interface{}
+	edgeDefaults map[string]interface{}
+}
+
+func (e parseEnv) enter(scope *parseScope) parseEnv {
+	return parseEnv{
+		scope:        scope,
+		nodeDefaults: copyAttributes(e.nodeDefaults),
+		edgeDefaults: copyAttributes(e.edgeDefaults),
+	}
+}
+
+func copyAttributes(m map[string]interface{}) map[string]interface{} {
+	c := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+var rankGroupNames = map[string]bool{"same": true, "min": true, "source": true, "max": true, "sink": true}
+
+// graphBuilder creates a Graph from a syntax tree in two passes.
+// The first pass decides in which subgraph each node is stored, the second pass creates all elements.
+type graphBuilder struct {
+	ast       *astGraph
+	root      *parseScope
+	scopes    map[*astSubgraph]*parseScope
+	named     map[string]*parseScope
+	home      map[string]*parseScope
+	nodes     map[string]Node
+	anonymous int
+}
+
+func newGraphBuilder(ast *astGraph) *graphBuilder {
+	root := &parseScope{kind: scopeGraph, name: ast.id}
+	root.owner = root
+	return &graphBuilder{
+		ast:    ast,
+		root:   root,
+		scopes: map[*astSubgraph]*parseScope{},
+		named:  map[string]*parseScope{},
+		home:   map[string]*parseScope{},
+		nodes:  map[string]Node{},
+	}
+}
+
+func (b *graphBuilder) build() (*Graph, error) {
+	options := []GraphOption{Undirected, NodeIDOption{}}
+	if b.ast.directed {
+		options[0] = Directed
+	}
+	if b.ast.strict {
+		options = append(options, Strict)
+	}
+	g := NewGraph(options...)
+	if len(b.ast.id) > 0 {
+		g.SetID(b.ast.id)
+	}
+	b.root.graph = g
+	b.collect(b.ast.stmts, b.root)
+	env := parseEnv{scope: b.root, nodeDefaults: map[string]interface{}{}, edgeDefaults: map[string]interface{}{}}
+	b.buildStatements(b.ast.stmts, env)
+	return g, nil
+}
+
+// scopeOf returns the scope for a subgraph, creating it on first encounter.
+func (b *graphBuilder) scopeOf(sub *astSubgraph, enclosing *parseScope) *parseScope {
+	if s, ok := b.scopes[sub]; ok {
+		return s
+	}
+	if len(sub.id) > 0 {
+		if s, ok := b.named[sub.id]; ok {
+			// reopened subgraph
+			b.scopes[sub] = s
+			return s
+		}
+	}
+	s := &parseScope{kind: classify(sub), name: sub.id, graphParent: enclosing.owner}
+	if s.kind == scopeGraph {
+		s.owner = s
+	} else {
+		s.owner = enclosing.owner
+	}
+	if s.kind == scopeRank {
+		s.rank = rankOf(sub)
+	}
+	if len(sub.id) > 0 {
+		b.named[sub.id] = s
+	}
+	b.scopes[sub] = s
+	return s
+}
+
+// classify decides how a subgraph is represented in the Graph.
+func classify(sub *astSubgraph) scopeKind {
+	if len(sub.id) > 0 {
+		return scopeGraph
+	}
+	graphAttrs, rankOnly, nodes := 0, true, 0
+	for _, each := range sub.stmts {
+		switch stmt := each.(type) {
+		case *astAssign:
+			graphAttrs++
+			rankOnly = rankOnly && isRankAttr(stmt.attr)
+		case *astAttrStmt:
+			if stmt.kind == "graph" {
+				for _, attr := range stmt.attrs {
+					graphAttrs++
+					rankOnly = rankOnly && isRankAttr(attr)
+				}
+			} else {
+				rankOnly = false
+			}
+		case *astNodeStmt:
+			nodes++
+			rankOnly = rankOnly && len(stmt.attrs) == 0 && len(stmt.node.port) == 0
+		default:
+			rankOnly = false
+		}
+	}
+	if graphAttrs == 0 {
+		return scopeTransparent
+	}
+	if rankOnly && nodes > 0 && len(rankOf(sub)) > 0 {
+		return scopeRank
+	}
+	return scopeGraph
+}
+
+func isRankAttr(attr astAttr) bool {
+	s, ok := attr.value.(string)
+	return attr.key == "rank" && ok && rankGroupNames[s]
+}
+
+// rankOf returns the last rank value set in the subgraph.
+func rankOf(sub *astSubgraph) (rank string) {
+	for _, each := range sub.stmts {
+		switch stmt := each.(type) {
+		case *astAssign:
+			if isRankAttr(stmt.attr) {
+				rank = stmt.attr.value.(string)
+			}
+		case *astAttrStmt:
+			for _, attr := range stmt.attrs {
+				if isRankAttr(attr) {
+					rank = attr.value.(string)
+				}
+			}
+		}
+	}
+	return
+}
+
+// collect is the first pass ; it records the deepest subgraph in which each node is mentioned.
+func (b *graphBuilder) collect(stmts []astStmt, scope *parseScope) {
+	for _, each := range stmts {
+		switch stmt := each.(type) {
+		case *astNodeStmt:
+			b.mention(stmt.node.id, scope)
+		case *astEdgeStmt:
+			for _, operand := range stmt.operands {
+				switch op := operand.(type) {
+				case astNodeID:
+					b.mention(op.id, scope)
+				case *astSubgraph:
+					b.collect(op.stmts, b.scopeOf(op, scope))
+				}
+			}
+		case *astSubgraph:
+			b.collect(stmt.stmts, b.scopeOf(stmt, scope))
+		}
+	}
+}
+
+func (b *graphBuilder) mention(id string, scope *parseScope) {
+	owner := scope.owner
+	home, ok := b.home[id]
+	if !ok || owner.isBelow(home) {
+		b.home[id] = owner
+	}
+}
+
+// graphFor returns the Graph of a scope, creating it (and its parents) if needed.
+func (b *graphBuilder) graphFor(scope *parseScope) *Graph {
+	if scope.graph != nil {
+		return scope.graph
+	}
+	parent := b.graphFor(scope.graphParent)
+	key := scope.name
+	if len(key) == 0 {
+		b.anonymous++
+		key = fmt.Sprintf("anonymous%d", b.anonymous)
+	}
+	sub := parent.Subgraph(key)
+	sub.id = scope.name
+	sub.Delete("label")
+	scope.graph = sub
+	return sub
+}
+
+// node returns the node for the id, creating it in its subgraph if needed.
+func (b *graphBuilder) node(id string, env parseEnv) Node {
+	if n, ok := b.nodes[id]; ok {
+		return n
+	}
+	n := b.graphFor(b.home[id]).Node(id)
+	for k, v := range env.nodeDefaults {
+		n.SetAttribute(k, v)
+	}
+	b.nodes[id] = n
+	return n
+}
+
+// buildStatements is the second pass ; it returns the nodes mentioned, in order of appearance.
+func (b *graphBuilder) buildStatements(stmts []astStmt, env parseEnv) []Node {
+	mentioned := []Node{}
+	g := b.graphFor(env.scope.owner)
+	for _, each := range stmts {
+		switch stmt := each.(type) {
+		case *astAssign:
+			g.SetAttribute(stmt.attr.key, stmt.attr.value)
+		case *astAttrStmt:
+			for _, attr := range stmt.attrs {
+				switch stmt.kind {
+				case "graph":
+					g.SetAttribute(attr.key, attr.value)
+				case "node":
+					env.nodeDefaults[attr.key] = attr.value
+				case "edge":
+					env.edgeDefaults[attr.key] = attr.value
+				}
+			}
+		case *astNodeStmt:
+			n := b.node(stmt.node.id, env)
+			for _, attr := range stmt.attrs {
+				n.SetAttribute(attr.key, attr.value)
+			}
+			mentioned = append(mentioned, n)
+		case *astEdgeStmt:
+			mentioned = append(mentioned, b.buildEdges(stmt, env)...)
+		case *astSubgraph:
+			mentioned = append(mentioned, b.buildSubgraph(stmt, env)...)
+		}
+	}
+	return mentioned
+}
+
+func (b *graphBuilder) buildSubgraph(sub *astSubgraph, env parseEnv) []Node {
+	scope := b.scopeOf(sub, env.scope)
+	if scope.kind != scopeRank {
+		return b.buildStatements(sub.stmts, env.enter(scope))
+	}
+	nodes := []Node{}
+	for _, each := range sub.stmts {
+		if stmt, ok := each.(*astNodeStmt); ok {
+			nodes = append(nodes, b.node(stmt.node.id, env))
+		}
+	}
+	g := b.graphFor(scope.owner)
+	switch scope.rank {
+	case "same":
+		g.AddToSameRank(nodes...)
+	case "min":
+		g.AddToMinRank(nodes...)
+	case "source":
+		g.AddToSourceRank(nodes...)
+	case "max":
+		g.AddToMaxRank(nodes...)
+	case "sink":
+		g.AddToSinkRank(nodes...)
+	}
+	return nodes
+}
+
+// edgeEnd is a node with an optional port.
+type edgeEnd struct {
+	node Node
+	port string
+}
+
+func (b *graphBuilder) buildEdges(stmt *astEdgeStmt, env parseEnv) []Node {
+	mentioned := []Node{}
+	ends := make([][]edgeEnd, len(stmt.operands))
+	for i, operand := range stmt.operands {
+		switch op := operand.(type) {
+		case astNodeID:
+			n := b.node(op.id, env)
+			ends[i] = []edgeEnd{{node: n, port: op.port}}
+			mentioned = append(mentioned, n)
+		case *astSubgraph:
+			for _, n := range b.buildSubgraph(op, env) {
+				ends[i] = append(ends[i], edgeEnd{node: n})
+				mentioned = append(mentioned, n)
+			}
+		}
+	}
+	g := b.graphFor(env.scope.owner)
+	for i := 1; i < len(ends); i++ {
+		for _, from := range ends[i-1] {
+			for _, to := range ends[i] {
+				e := g.EdgeWithPorts(from.node, to.node, from.port, to.port)
+				for k, v := range env.edgeDefaults {
+					e.SetAttribute(k, v)
+				}
+				for _, attr := range stmt.attrs {
+					e.SetAttribute(attr.key, attr.value)
+				}
+			}
+		}
+	}
+	return mentioned
+}
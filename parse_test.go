@@ -0,0 +1,214 @@
+package dot
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseEmpty(t *testing.T) {
+	g, err := ParseString(`digraph {}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := flatten(g.String()), `digraph  {}`; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestParseHeader(t *testing.T) {
+	g, err := ParseString(`STRICT Graph "my graph" {}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := g.ID(), "my graph"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if g.IsDirected() || !g.isStrict {
+		t.Errorf("expected strict undirected graph")
+	}
+}
+
+func TestParseNodesAndEdges(t *testing.T) {
+	g, err := ParseString(`digraph G {
+		// comment
+		A [label="Node A" shape=box]
+		A -> B -> C [color=red]; /* block comment */
+		C -> A
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := flatten(g.String()), `digraph G {A[label="Node A",shape="box"];B[label="B"];C[label="C"];A->B[color="red"];B->C[color="red"];C->A;}`; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestParseValueKinds(t *testing.T) {
+	g, err := ParseString(`digraph {
+		a [label=<<B>bold</B>>]
+		b [label="left\l" tooltip="say \"hi\""]
+		c [label="con" + "cat", width=1.5]
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, _ := g.FindNodeById("a")
+	if got, want := a.Value("label"), HTML("<B>bold</B>"); got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	b, _ := g.FindNodeById("b")
+	if got, want := b.Value("label"), Literal(`"left\l"`); got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := b.Value("tooltip"), `say "hi"`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	c, _ := g.FindNodeById("c")
+	if got, want := c.Value("label"), "concat"; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := c.Value("width"), "1.5"; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestWriteEscapesRoundTrip(t *testing.T) {
+	g := NewGraph(Directed, NodeIDOption{})
+	g.Node("a").Label(`C:\dir "x"`).Tooltip("two\nlines")
+	g.Node(`back\slash`).SetAttribute("label", Literal(`"left\l"`))
+	if got, want := flatten(g.String()), `digraph  {a[label="C:\\dir \"x\"",tooltip="two\nlines"];"back\slash"[label="left\l"];}`; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	parsed, err := ParseString(g.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g.Equal(parsed) {
+		t.Errorf("expected equal graphs:\n%s\n%s", g.String(), parsed.String())
+	}
+	b, _ := parsed.FindNodeById(`back\slash`)
+	if got, want := b.Value("label"), Literal(`"left\l"`); got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := quoteID(`back\slash"`), `"back\slash\""`; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := quoteID(`trailing\`), `"trailing\\"`; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestParsePorts(t *testing.T) {
+	g, err := ParseString(`digraph { a:p1:se -> b:n; c:"my port" -> d }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := flatten(g.String()), `digraph  {a[label="a"];b[label="b"];c[label="c"];d[label="d"];a:p1:se->b:n;c:"my port"->d;}`; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestParseSubgraphs(t *testing.T) {
+	g, err := ParseString(`digraph {
+		start -> a0
+		subgraph cluster_0 {
+			label="process #1"
+			a0 -> a1
+			subgraph cluster_inner { a1 }
+		}
+		{ rank=same; start; a0 }
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outer, ok := g.FindSubgraph("cluster_0")
+	if !ok {
+		t.Fatal("missing cluster_0")
+	}
+	if got, want := outer.ID(), "cluster_0"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	inner, ok := outer.FindSubgraph("cluster_inner")
+	if !ok {
+		t.Fatal("missing cluster_inner")
+	}
+	a0, _ := g.FindNodeById("a0")
+	if got, want := a0.Graph(), outer; got != want {
+		t.Errorf("a0 got [%v] want [%v]", got.ID(), want.ID())
+	}
+	a1, _ := g.FindNodeById("a1")
+	if got, want := a1.Graph(), inner; got != want {
+		t.Errorf("a1 got [%v] want [%v]", got.ID(), want.ID())
+	}
+	if got, want := len(g.sameRanks["same"]), 2; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestParseDefaults(t *testing.T) {
+	g, err := ParseString(`graph {
+		a
+		node [shape=box]
+		edge [color=blue]
+		b -- c
+		{ node [shape=circle] d }
+		e
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for id, want := range map[string]interface{}{"a": nil, "b": "box", "c": "box", "d": "circle", "e": "box"} {
+		n, _ := g.FindNodeById(id)
+		if got := n.Value("shape"); got != want {
+			t.Errorf("%s: got [%v] want [%v]", id, got, want)
+		}
+	}
+	if got, want := g.EdgesMap()["b"][0].Value("color"), "blue"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestParseSubgraphOperand(t *testing.T) {
+	g, err := ParseString(`digraph { a -> { b c } -> d }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := flatten(g.String()), `digraph  {a[label="a"];b[label="b"];c[label="c"];d[label="d"];a->b;a->c;b->d;c->d;}`; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, each := range []struct {
+		src          string
+		line, column int
+	}{
+		{"digraph {\n a -> \n}", 3, 1},
+		{"graph {\n\ta -> b\n}", 2, 4},
+		{"digraph { a [color] }", 1, 19},
+		{"digraph {\n  \"open\n}", 2, 3},
+		{"tree {}", 1, 1},
+		{"digraph {} extra", 1, 12},
+	} {
+		_, err := ParseString(each.src)
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Errorf("%q: expected ParseError, got %v", each.src, err)
+			continue
+		}
+		if perr.Line != each.line || perr.Column != each.column {
+			t.Errorf("%q: got %d:%d want %d:%d (%v)", each.src, perr.Line, perr.Column, each.line, each.column, perr)
+		}
+	}
+}
+
+func TestParseReader(t *testing.T) {
+	g, err := Parse(strings.NewReader("# preprocessor line\ndigraph { x }"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := g.FindNodeById("x"); !ok {
+		t.Fail()
+	}
+}
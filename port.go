@@ -0,0 +1,153 @@
+package dot
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Port is a place on a node where an edge attaches: the name of a field of a record label
+// or a cell of an HTML-like label, optionally followed by a compass point, such as "out:se".
+// A compass point alone, such as "n", is also a port ; every node has these.
+type Port string
+
+// Compass is a point on the boundary of a node or a field of its label.
+type Compass string
+
+// Compass points ; CompassAny lets Graphviz choose the side.
+const (
+	N          Compass = "n"
+	NE         Compass = "ne"
+	E          Compass = "e"
+	SE         Compass = "se"
+	S          Compass = "s"
+	SW         Compass = "sw"
+	W          Compass = "w"
+	NW         Compass = "nw"
+	C          Compass = "c"
+	CompassAny Compass = "_"
+)
+
+var compassPoints = []Compass{N, NE, E, SE, S, SW, W, NW, C, CompassAny}
+
+func isCompass(text string) bool {
+	for _, each := range compassPoints {
+		if string(each) == text {
+			return true
+		}
+	}
+	return false
+}
+
+// Compass returns the port with the compass point, replacing any it had.
+func (p Port) Compass(c Compass) Port {
+	if len(p.Name()) == 0 {
+		return Port(c)
+	}
+	return Port(p.Name() + ":" + string(c))
+}
+
+// Name returns the name of the field or cell ; empty if the port is a compass point only.
+func (p Port) Name() string {
+	name, _ := p.split()
+	return name
+}
+
+// CompassPoint returns the compass point of the port ; empty if it has none.
+func (p Port) CompassPoint() Compass {
+	_, c := p.split()
+	return c
+}
+
+func (p Port) split() (string, Compass) {
+	text := string(p)
+	if isCompass(text) {
+		return "", Compass(text)
+	}
+	if i := strings.LastIndex(text, ":"); i != -1 && isCompass(text[i+1:]) {
+		return text[:i], Compass(text[i+1:])
+	}
+	return text, ""
+}
+
+// PortError describes an edge that attaches to a port that the label of its node does not have.
+type PortError struct {
+	Node string
+	Port Port
+	// Ports are those of the label of the node ; nil if the node has no record or HTML-like label.
+	Ports []string
+}
+
+func (e *PortError) Error() string {
+	if e.Ports == nil {
+		return fmt.Sprintf("node %q has no record or HTML-like label for port %q", e.Node, e.Port.Name())
+	}
+	return fmt.Sprintf("node %q has no port %q, only %s", e.Node, e.Port.Name(), strings.Join(e.Ports, ", "))
+}
+
+// checkPort returns a *PortError if the name of the port is not in the label of the node ; nil otherwise.
+func checkPort(n Node, p Port) error {
+	name := p.Name()
+	if len(name) == 0 {
+		return nil
+	}
+	ports, ok := n.Ports()
+	for _, each := range ports {
+		if each == name {
+			return nil
+		}
+	}
+	if !ok {
+		ports = nil
+	}
+	return &PortError{Node: n.id, Port: p, Ports: ports}
+}
+
+// PortErrors returns an error for each port of an edge of the graph, or its subgraphs,
+// that is not in the record or HTML-like label of its node.
+func (g *Graph) PortErrors() []*PortError {
+	errs := []*PortError{}
+	var visit func(each *Graph)
+	visit = func(each *Graph) {
+		for _, id := range each.sortedEdgesFromKeys() {
+			for _, e := range each.edgesFrom[id] {
+				errs = appendPortError(errs, e.from, Port(e.fromPort))
+				errs = appendPortError(errs, e.to, Port(e.toPort))
+			}
+		}
+		for _, key := range each.sortedSubgraphsKeys() {
+			visit(each.subgraphs[key])
+		}
+	}
+	visit(g)
+	return errs
+}
+
+func appendPortError(errs []*PortError, n Node, p Port) []*PortError {
+	if err := checkPort(n, p); err != nil {
+		return append(errs, err.(*PortError))
+	}
+	return errs
+}
+
+// htmlPortPattern matches the PORT attribute of an element of an HTML-like label.
+var htmlPortPattern = regexp.MustCompile(`(?i)\bport\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+
+// Ports returns the port names of the record or HTML-like label of the node, in order of appearance.
+// Returns false if the node has neither a record shape nor an HTML-like label.
+func (n Node) Ports() ([]string, bool) {
+	attributes := n.EffectiveAttributes()
+	if label, ok := attributes["label"].(HTML); ok {
+		ports := []string{}
+		for _, each := range htmlPortPattern.FindAllStringSubmatch(string(label), -1) {
+			ports = append(ports, each[1]+each[2])
+		}
+		return ports, true
+	}
+	if isRecordShape(attributes["shape"]) {
+		// an invalid label has no ports
+		fields, _ := n.RecordFields()
+		return RecordPorts(fields), true
+	}
+	return nil, false
+}
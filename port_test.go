@@ -0,0 +1,109 @@
+package dot
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPortCompass(t *testing.T) {
+	for p, want := range map[Port]string{
+		Port("out").Compass(SE):           "out|se|out:se",
+		Port("out:ne").Compass(W):         "out|w|out:w",
+		Port(N):                           "|n|n",
+		Port("").Compass(S):               "|s|s",
+		Port("a:b"):                       "a:b||a:b",
+		Port("field").Compass(CompassAny): "field|_|field:_",
+	} {
+		if got := p.Name() + "|" + string(p.CompassPoint()) + "|" + string(p); got != want {
+			t.Errorf("got [%v] want [%v]", got, want)
+		}
+	}
+}
+
+func TestPortEdgeWithRecord(t *testing.T) {
+	g := NewGraph(Directed)
+	rb := g.Node("r").NewRecordBuilder()
+	out := rb.FieldWithPort("out", "o")
+	rb.FieldWithId("in", "i").Field("x")
+	rb.Build()
+	other := g.Node("x")
+	g.Node("r").PortEdge(out.Compass(SE), other, Port(N))
+	if got, want := flatten(g.String()), `digraph  {n1[label="<o> out|<i> in|x",shape="record"];n2[label="x"];n1:o:se->n2:n;}`; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got := g.PortErrors(); len(got) != 0 {
+		t.Errorf("unexpected errors %v", got)
+	}
+}
+
+func TestPortErrors(t *testing.T) {
+	g := NewGraph(Directed)
+	r := g.Node("r").SetAttribute("shape", "record").Label("<a> a|<b> b")
+	h := g.Node("h").SetAttribute("label", HTML(`<table><tr><td port="p1">1</td></tr></table>`))
+	sub := g.Subgraph("sub")
+	plain := sub.Node("plain")
+	g.PortEdge(r, "a:e", h, "p1")
+	g.PortEdge(r, "x", h, "p2")
+	sub.PortEdge(plain, "left", plain, Port(W))
+	got := []string{}
+	for _, each := range g.PortErrors() {
+		got = append(got, each.Error())
+	}
+	want := []string{
+		`node "r" has no port "x", only a, b`,
+		`node "h" has no port "p2", only p1`,
+		`node "plain" has no record or HTML-like label for port "left"`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestPortErrorsStrict(t *testing.T) {
+	g := NewGraph(Directed, StrictAttributesOption{})
+	r := g.Node("r").SetAttribute("shape", "record").Label("<a> a")
+	g.PortEdge(r, "a", r, "b:n")
+	got := []string{}
+	for _, each := range g.AttributeErrors() {
+		got = append(got, each.Error())
+	}
+	if got, want := strings.Join(got, "\n"), `edge attribute headport=b:n: node "r" has no port "b", only a`; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestPortErrorPanics(t *testing.T) {
+	g := NewGraph(Directed, StrictAttributesOption{Panic: true})
+	r := g.Node("r").SetAttribute("shape", "record").Label("<a> a")
+	defer func() {
+		if r := recover(); r != `node "r" has no port "b", only a` {
+			t.Errorf("unexpected panic %v", r)
+		}
+	}()
+	g.PortEdge(r, "b", r, "a")
+}
+
+func TestPortErrorPanicsOnWrite(t *testing.T) {
+	g := NewGraph(Directed, StrictAttributesOption{Panic: true})
+	r := g.Node("r").SetAttribute("shape", "record").Label("<a> a|<b> b")
+	g.PortEdge(r, "b", r, "a")
+	r.Label("<a> a")
+	defer func() {
+		if r := recover(); r != `node "r" has no port "b", only a` {
+			t.Errorf("unexpected panic %v", r)
+		}
+	}()
+	_ = g.String()
+}
+
+func TestNodePorts(t *testing.T) {
+	g := NewGraph(Directed)
+	g.NodeDefaults().SetAttribute("shape", "Mrecord")
+	ports, ok := g.Node("n").Label(`<x> x|{<y\>> y|\<z\>}`).Ports()
+	if got, want := strings.Join(ports, ","), "x,y>"; !ok || got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if _, ok := NewGraph().Node("n").Ports(); ok {
+		t.Error("expected no ports")
+	}
+}
@@ -52,13 +52,82 @@ func (r recordField) writeOn(buf *strings.Builder) {
 type recordFieldId struct {
 	id      string
 	content string
+	align   FieldAlignment
+	// style is only used for HTML-like tables, see Fields
+	style FieldStyle
 }
 
 func (r recordFieldId) writeOn(buf *strings.Builder) {
 	if r.id != "" {
-		fmt.Fprintf(buf, "<%s> ", r.id)
+		fmt.Fprintf(buf, "<%s> ", escapeRecordText(r.id))
 	}
-	buf.WriteString(r.content)
+	buf.WriteString(escapeRecordText(r.text()))
+}
+
+// text returns the content with its lines terminated by the escape sequence of the alignment.
+func (r recordFieldId) text() string {
+	lines := strings.Split(r.content, "\n")
+	if r.align == FieldAlignCenter {
+		return strings.Join(lines, `\n`)
+	}
+	terminator := map[FieldAlignment]string{FieldAlignLeft: `\l`, FieldAlignRight: `\r`}[r.align]
+	return strings.Join(lines, terminator) + terminator
+}
+
+// FieldOption changes a field added by a record builder ; see FieldAlignment and FieldStyle.
+type FieldOption interface {
+	applyToField(f *recordFieldId)
+}
+
+// FieldAlignment is the horizontal alignment of the lines of a record field.
+type FieldAlignment int
+
+const (
+	FieldAlignCenter FieldAlignment = iota
+	FieldAlignLeft
+	FieldAlignRight
+)
+
+func (a FieldAlignment) applyToField(f *recordFieldId) { f.align = a }
+
+// FieldStyle has the colors and font of a field. Records cannot show these ;
+// use the fields of the builder to create an HTML-like table instead, see package htmlike.
+type FieldStyle struct {
+	BGColor   Color
+	FontColor Color
+	FontName  string
+	FontSize  float64
+	Bold      bool
+	Italic    bool
+}
+
+func (s FieldStyle) applyToField(f *recordFieldId) { f.style = s }
+
+// escapeRecordText escapes the characters that structure a record label: braces, bars, angle brackets
+// and spaces that would otherwise be dropped (leading, trailing or repeated).
+// Backslash sequences, such as \l or \{, are kept as is ; a trailing backslash is escaped.
+func escapeRecordText(text string) string {
+	buf := new(strings.Builder)
+	last := len(text) - 1
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case c == '\\' && i < last:
+			buf.WriteByte(c)
+			i++
+			buf.WriteByte(text[i])
+			continue
+		case c == '\\':
+			// a trailing backslash would escape what follows the field
+			buf.WriteByte(c)
+		case strings.IndexByte("{}|<>", c) != -1:
+			buf.WriteByte('\\')
+		case c == ' ' && (i == 0 || i == last || text[i-1] == ' '):
+			buf.WriteByte('\\')
+		}
+		buf.WriteByte(c)
+	}
+	return buf.String()
 }
 
 // MRecord sets the shape of the node to "mrecord"
@@ -67,55 +136,105 @@ func (r *recordBuilder) MRecord() *recordBuilder {
 	return r
 }
 
-// Field adds a record field
-func (r *recordBuilder) Field(content string) *recordBuilder {
-	rf := recordField{
-		id: recordFieldId{
-			content: content,
-		},
-	}
-	r.currentLabel = append(r.currentLabel, rf)
+// Field adds a record field ; characters of the record syntax in the content are escaped.
+// Each line of the content (separated by \n) is written with the alignment of the options ; centered by default.
+// Backslash sequences in the content, such as \l or \{, are passed through to Graphviz unchanged ;
+// write \\ for a literal backslash.
+func (r *recordBuilder) Field(content string, options ...FieldOption) *recordBuilder {
+	r.addField("", content, options)
 	return r
 }
 
-// FieldWithId adds a record field with an identifier for connecting edges.
-func (r *recordBuilder) FieldWithId(content, id string) *recordBuilder {
-	rf := recordField{
-		id: recordFieldId{
-			id:      id,
-			content: content,
-		},
-	}
-	r.currentLabel = append(r.currentLabel, rf)
+// FieldWithId adds a record field with an identifier for connecting edges ; see Field for escaping and options.
+// Use FieldWithPort to get the Port of the field instead of the builder.
+func (r *recordBuilder) FieldWithId(content, id string, options ...FieldOption) *recordBuilder {
+	r.addField(id, content, options)
 	return r
 }
 
-// Nesting will create a nested (layout flipped) list of rlabel.
+// FieldWithPort adds a record field like FieldWithId and returns the Port of the field, see Graph.PortEdge.
+func (r *recordBuilder) FieldWithPort(content, id string, options ...FieldOption) Port {
+	r.addField(id, content, options)
+	return Port(id)
+}
+
+func (r *recordBuilder) addField(id, content string, options []FieldOption) {
+	f := recordFieldId{id: id, content: content}
+	for _, each := range options {
+		each.applyToField(&f)
+	}
+	r.currentLabel = append(r.currentLabel, recordField{id: f})
+}
+
+// Nesting will create a nested (layout flipped) list of rlabel ; a block without fields adds nothing.
+// The builder is restored if the block panics ; Build, Label and Fields called within a block use the whole record.
 func (r *recordBuilder) Nesting(block func()) {
 	r.nesting.push(r.currentLabel)
 	r.currentLabel = recordLabel{}
+	defer func() {
+		// currentLabel has fields added by block
+		// top of stack has label before block
+		top := r.nesting.pop()
+		if len(r.currentLabel) > 0 {
+			cpy := r.currentLabel[:]
+			top = append(top, recordField{
+				nestedLabel: &cpy,
+			})
+		}
+		r.currentLabel = top
+	}()
 	block()
-	// currentLabel has fields added by block
-	// top of stack has label before block
-	top := r.nesting.pop()
-	cpy := r.currentLabel[:]
-	top = append(top, recordField{
-		nestedLabel: &cpy,
-	})
-	r.currentLabel = top
 }
 
-// Build sets the computed label and shape
+// wholeLabel returns the fields added so far, with those of the blocks being nested in their enclosing label.
+func (r *recordBuilder) wholeLabel() recordLabel {
+	label := r.currentLabel
+	for i := len(*r.nesting) - 1; i >= 0; i-- {
+		enclosing := append(recordLabel{}, (*r.nesting)[i]...)
+		if len(label) > 0 {
+			cpy := label
+			enclosing = append(enclosing, recordField{nestedLabel: &cpy})
+		}
+		label = enclosing
+	}
+	return label
+}
+
+// Build sets the computed label and shape.
+// The label is a Literal if it has escape sequences, so that its backslashes are written as is.
 func (r *recordBuilder) Build() error {
 	r.target.SetAttribute("shape", r.shape)
-	r.target.SetAttribute("label", r.Label())
+	label := r.Label()
+	if strings.Contains(label, `\`) {
+		r.target.SetAttribute("label", Literal(`"`+strings.ReplaceAll(label, `"`, `\"`)+`"`))
+	} else {
+		r.target.SetAttribute("label", label)
+	}
 	return nil
 }
 
+// Fields returns the fields added so far, including their styles, such as for creating an HTML-like table.
+// The text of each field is as ParseRecordLabel returns it for the built label.
+func (r *recordBuilder) Fields() []RecordField {
+	return r.wholeLabel().fields()
+}
+
+func (r recordLabel) fields() []RecordField {
+	fields := []RecordField{}
+	for _, each := range r {
+		if each.nestedLabel != nil {
+			fields = append(fields, RecordField{Fields: each.nestedLabel.fields()})
+			continue
+		}
+		fields = append(fields, RecordField{Port: each.id.id, Text: each.id.text(), Style: each.id.style})
+	}
+	return fields
+}
+
 // Label returns the computed label
 func (r *recordBuilder) Label() string {
 	buf := new(strings.Builder)
-	for i, each := range r.currentLabel {
+	for i, each := range r.wholeLabel() {
 		if i > 0 {
 			buf.WriteString("|")
 		}
@@ -124,6 +243,187 @@ func (r *recordBuilder) Label() string {
 	return buf.String()
 }
 
+// RecordField is a field of a record label: text with an optional port,
+// or a list of nested fields that is laid out in the other direction.
+type RecordField struct {
+	// Port is the name of the field for connecting edges ; empty if it has none.
+	Port string
+	// Text is the content with escaped record characters unescaped ; other backslash sequences, such as \l, are kept.
+	Text string
+	// Fields are the nested fields ; nil for a text field.
+	Fields []RecordField
+	// Style is set by the options of a record builder ; ParseRecordLabel leaves it empty.
+	Style FieldStyle
+}
+
+// IsNested returns whether the field has nested fields instead of text.
+func (f RecordField) IsNested() bool {
+	return f.Fields != nil
+}
+
+// ParseRecordLabel returns the fields of the label of a node with shape "record" or "Mrecord".
+// Returns a *ParseError if the braces or angle brackets of the label are not balanced.
+func ParseRecordLabel(label string) ([]RecordField, error) {
+	p := &recordParser{label: label}
+	fields, err := p.fields(0)
+	if err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// RecordFields returns the fields of the label of the node, which must have shape "record" or "Mrecord".
+func (n Node) RecordFields() ([]RecordField, error) {
+	attributes := n.EffectiveAttributes()
+	if !isRecordShape(attributes["shape"]) {
+		return nil, fmt.Errorf("node %q has no record shape", n.id)
+	}
+	label, ok := labelText(attributes["label"])
+	if !ok {
+		return nil, fmt.Errorf("node %q has no record label", n.id)
+	}
+	return ParseRecordLabel(label)
+}
+
+// RecordPorts returns the ports of the fields, including nested ones, in order of appearance.
+func RecordPorts(fields []RecordField) []string {
+	ports := []string{}
+	for _, each := range fields {
+		if each.IsNested() {
+			ports = append(ports, RecordPorts(each.Fields)...)
+		} else if len(each.Port) > 0 {
+			ports = append(ports, each.Port)
+		}
+	}
+	return ports
+}
+
+// isRecordShape returns whether the value of a shape attribute is "record" or "Mrecord" (in any case).
+func isRecordShape(shape interface{}) bool {
+	s, ok := shape.(string)
+	return ok && (strings.EqualFold(s, "record") || strings.EqualFold(s, "Mrecord"))
+}
+
+// recordParser reads the fields of a record label.
+type recordParser struct {
+	label string
+	pos   int
+}
+
+func (p *recordParser) fail(format string, args ...interface{}) error {
+	return &ParseError{Line: 1, Column: p.pos + 1, Message: fmt.Sprintf(format, args...)}
+}
+
+// fields reads fields separated by bars until the end of the label or, if nested, a closing brace.
+func (p *recordParser) fields(depth int) ([]RecordField, error) {
+	fields := []RecordField{}
+	for {
+		field, err := p.field()
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+		if p.pos == len(p.label) {
+			if depth > 0 {
+				return nil, p.fail("missing }")
+			}
+			return fields, nil
+		}
+		if p.label[p.pos] == '}' {
+			if depth == 0 {
+				return nil, p.fail("unexpected }")
+			}
+			p.pos++
+			return fields, nil
+		}
+		// a bar
+		p.pos++
+	}
+}
+
+// field reads a nested list of fields or text with an optional port, up to the next bar or closing brace.
+func (p *recordParser) field() (RecordField, error) {
+	p.skipSpaces()
+	if p.pos < len(p.label) && p.label[p.pos] == '{' {
+		p.pos++
+		nested, err := p.fields(1)
+		if err != nil {
+			return RecordField{}, err
+		}
+		p.skipSpaces()
+		if p.pos < len(p.label) && p.label[p.pos] != '|' && p.label[p.pos] != '}' {
+			return RecordField{}, p.fail("expected | or } after nested fields")
+		}
+		return RecordField{Fields: nested}, nil
+	}
+	text, port := new(recordText), new(recordText)
+	inPort, hasPort := false, false
+	for ; p.pos < len(p.label); p.pos++ {
+		c := p.label[p.pos]
+		target := text
+		if inPort {
+			target = port
+		}
+		switch {
+		case c == '\\' && p.pos+1 < len(p.label):
+			p.pos++
+			if strings.IndexByte("{}|<> ", p.label[p.pos]) == -1 {
+				target.write(c, true)
+			}
+			target.write(p.label[p.pos], true)
+		case c == '<':
+			if inPort || hasPort {
+				return RecordField{}, p.fail("unexpected <")
+			}
+			inPort, hasPort = true, true
+		case c == '>':
+			if !inPort {
+				return RecordField{}, p.fail("unexpected >")
+			}
+			inPort = false
+		case c == '{':
+			return RecordField{}, p.fail("unexpected {")
+		case c == '|' || c == '}':
+			if inPort {
+				return RecordField{}, p.fail("missing >")
+			}
+			return RecordField{Port: port.String(), Text: text.String()}, nil
+		default:
+			target.write(c, false)
+		}
+	}
+	if inPort {
+		return RecordField{}, p.fail("missing >")
+	}
+	return RecordField{Port: port.String(), Text: text.String()}, nil
+}
+
+// recordText collects the text of a field or port without leading and trailing unescaped spaces.
+type recordText struct {
+	strings.Builder
+	// spaces are unescaped spaces to write before the next character
+	spaces int
+}
+
+func (t *recordText) write(c byte, escaped bool) {
+	if c == ' ' && !escaped {
+		if t.Len() > 0 {
+			t.spaces++
+		}
+		return
+	}
+	for ; t.spaces > 0; t.spaces-- {
+		t.WriteByte(' ')
+	}
+	t.WriteByte(c)
+}
+
+func (p *recordParser) skipSpaces() {
+	for p.pos < len(p.label) && p.label[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
 // stack implements a lifo queue for recordLabel instances.
 type stack []recordLabel
 
@@ -2,6 +2,7 @@ package dot
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -59,6 +60,33 @@ func TestTwoColumnsNestedRecord(t *testing.T) {
 	}
 }
 
+func TestNestingMRecord(t *testing.T) {
+	g := NewGraph(Directed)
+
+	rb := newRecordBuilder(g.Node("r")).MRecord()
+	rb.Field("a")
+	rb.Nesting(func() {})
+	rb.Nesting(func() {
+		rb.Field("b")
+		rb.Nesting(func() {
+			rb.FieldWithId("c", "c")
+			rb.Build()
+		})
+	})
+	func() {
+		defer func() { recover() }()
+		rb.Nesting(func() { panic("block") })
+	}()
+	rb.Field("d")
+
+	if got, want := flatten(g.String()), `digraph  {n1[label="a|{b|{<c> c}}",shape="mrecord"];}`; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := rb.Label(), "a|{b|{<c> c}}|d"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
 func TestStack(t *testing.T) {
 	one := recordLabel{}
 	two := recordLabel{}
@@ -121,3 +149,118 @@ func ExampleNode_NewRecordBuilder() {
 	fmt.Println(flatten(g.String()))
 	// Output:digraph  {n1[label="<f0> left|<f1> mid&#92;dle|<f2> right",shape="record"];n2[label="<f0> one",shape="record"];n3[label="hello&#92;world|{b|{c|<here> d|e}|f}|g|h",shape="record"];n1:f1->n2:f0;n1:f2->n3:here;}
 }
+
+func TestRecordFieldEscaping(t *testing.T) {
+	g := NewGraph(Directed)
+	rb := newRecordBuilder(g.Node("r"))
+	rb.Field("{a|b}")
+	rb.FieldWithId(" <x>  y ", "p|1")
+	rb.Field(`left\l`)
+	rb.Build()
+	if got, want := flatten(g.String()), `digraph  {n1[label="\{a\|b\}|<p\|1> \ \<x\> \ y\ |left\l",shape="record"];}`; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	fields, err := g.Node("r").RecordFields()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := recordFieldsString(fields), `[""|"{a|b}"] ["p|1"|" <x>  y "] [""|"left\\l"]`; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestRecordFieldBackslash(t *testing.T) {
+	g := NewGraph(Directed)
+	rb := newRecordBuilder(g.Node("r"))
+	rb.Field(`C:\\dir`)
+	rb.Field(`end\`)
+	rb.Build()
+	if got, want := flatten(g.String()), `digraph  {n1[label="C:\\dir|end\\",shape="record"];}`; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	fields, err := g.Node("r").RecordFields()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := recordFieldsString(fields), `[""|"C:\\\\dir"] [""|"end\\\\"]`; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestParseRecordLabel(t *testing.T) {
+	fields, err := ParseRecordLabel(`hello\nworld |{ b |{c|<here> d|e}| f}| <g>| h`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(fields), 4; got != want {
+		t.Fatalf("got [%v] want [%v]", got, want)
+	}
+	if got, want := fields[0].Text, `hello\nworld`; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	nested := fields[1].Fields[1]
+	if got, want := fmt.Sprintf("%v %v %s", fields[1].IsNested(), nested.IsNested(), recordFieldsString(nested.Fields)), `true true [""|"c"] ["here"|"d"] [""|"e"]`; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := fmt.Sprint(RecordPorts(fields)), "[here g]"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if _, err := NewGraph().Node("n").RecordFields(); err == nil || err.Error() != `node "n" has no record shape` {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestParseRecordLabelErrors(t *testing.T) {
+	for label, want := range map[string]string{
+		"{a|b":    "line 1, column 5: missing }",
+		"a}":      "line 1, column 2: unexpected }",
+		"<a":      "line 1, column 3: missing >",
+		"a>":      "line 1, column 2: unexpected >",
+		"<a><b>":  "line 1, column 4: unexpected <",
+		"a{b}":    "line 1, column 2: unexpected {",
+		"{a} b|c": "line 1, column 5: expected | or } after nested fields",
+	} {
+		_, err := ParseRecordLabel(label)
+		if err == nil || err.Error() != want {
+			t.Errorf("%s: got [%v] want [%v]", label, err, want)
+		}
+	}
+}
+
+// recordFieldsString returns the port and text of each field, with nested fields in braces.
+func recordFieldsString(fields []RecordField) string {
+	parts := []string{}
+	for _, each := range fields {
+		if each.IsNested() {
+			parts = append(parts, "{"+recordFieldsString(each.Fields)+"}")
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("[%q|%q]", each.Port, each.Text))
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestRecordFieldAlignmentAndLines(t *testing.T) {
+	g := NewGraph(Directed)
+	rb := g.Node("r").NewRecordBuilder().MRecord()
+	rb.Field("name\nemail", FieldAlignLeft)
+	rb.Nesting(func() {
+		rb.FieldWithId("total", "t", FieldAlignRight, FieldStyle{BGColor: "yellow", Bold: true})
+		rb.Field("a\nb")
+	})
+	rb.Build()
+	if got, want := flatten(g.String()), `digraph  {n1[label="name\lemail\l|{<t> total\r|a\nb}",shape="mrecord"];}`; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	parsed, err := g.Node("r").RecordFields()
+	if err != nil {
+		t.Fatal(err)
+	}
+	built := rb.Fields()
+	if got, want := recordFieldsString(built), recordFieldsString(parsed); got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := built[1].Fields[0].Style, (FieldStyle{BGColor: "yellow", Bold: true}); got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
@@ -0,0 +1,352 @@
+package dot
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenID            // identifier, numeral, quoted string or HTML string
+	tokenLeftBrace
+	tokenRightBrace
+	tokenLeftBracket
+	tokenRightBracket
+	tokenSemicolon
+	tokenComma
+	tokenEqual
+	tokenColon
+	tokenDirectedEdge
+	tokenUndirectedEdge
+)
+
+func (k tokenKind) String() string {
+	switch k {
+	case tokenEOF:
+		return "end of input"
+	case tokenID:
+		return "identifier"
+	case tokenLeftBrace:
+		return "'{'"
+	case tokenRightBrace:
+		return "'}'"
+	case tokenLeftBracket:
+		return "'['"
+	case tokenRightBracket:
+		return "']'"
+	case tokenSemicolon:
+		return "';'"
+	case tokenComma:
+		return "','"
+	case tokenEqual:
+		return "'='"
+	case tokenColon:
+		return "':'"
+	case tokenDirectedEdge:
+		return "'->'"
+	case tokenUndirectedEdge:
+		return "'--'"
+	}
+	return "unknown token"
+}
+
+// token is a lexical element of the DOT language.
+type token struct {
+	kind tokenKind
+	// text is the identifier as DOT sees it: quotes removed and \" unescaped.
+	text string
+	// value is the attribute value for this identifier: a string, a Literal or an HTML.
+	value interface{}
+	// quoted is true if the identifier was a (concatenation of) quoted string(s).
+	quoted       bool
+	line, column int
+}
+
+func (t token) String() string {
+	if t.kind == tokenID {
+		return fmt.Sprintf("%q", t.text)
+	}
+	return t.kind.String()
+}
+
+// ParseError describes a problem found at a position in DOT or Mermaid source.
+type ParseError struct {
+	Line, Column int
+	Message      string
+}
+
+// Error returns the message prefixed by the position.
+func (e *ParseError) Error() string {
+	if e.Column == 0 {
+		return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+	}
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Message)
+}
+
+// scanner splits DOT source into tokens.
+type scanner struct {
+	src          string
+	offset       int
+	line, column int
+}
+
+func newScanner(src string) *scanner {
+	return &scanner{src: src, line: 1, column: 1}
+}
+
+func (s *scanner) errorf(line, column int, format string, args ...interface{}) error {
+	return &ParseError{Line: line, Column: column, Message: fmt.Sprintf(format, args...)}
+}
+
+func (s *scanner) peekRune(ahead int) rune {
+	offset := s.offset
+	for i := 0; i < ahead; i++ {
+		if offset >= len(s.src) {
+			return -1
+		}
+		_, size := utf8.DecodeRuneInString(s.src[offset:])
+		offset += size
+	}
+	if offset >= len(s.src) {
+		return -1
+	}
+	r, _ := utf8.DecodeRuneInString(s.src[offset:])
+	return r
+}
+
+func (s *scanner) nextRune() rune {
+	if s.offset >= len(s.src) {
+		return -1
+	}
+	r, size := utf8.DecodeRuneInString(s.src[s.offset:])
+	s.offset += size
+	if r == '\n' {
+		s.line++
+		s.column = 1
+	} else {
+		s.column++
+	}
+	return r
+}
+
+// skipSpace skips whitespace, comments and preprocessor output lines (starting with #).
+func (s *scanner) skipSpace() error {
+	for {
+		r := s.peekRune(0)
+		switch {
+		case r == ' ' || r == '\t' || r == '\r' || r == '\n' || r == '\f' || r == '\v':
+			s.nextRune()
+		case r == '#' && s.column == 1:
+			for r := s.peekRune(0); r != '\n' && r != -1; r = s.peekRune(0) {
+				s.nextRune()
+			}
+		case r == '/' && s.peekRune(1) == '/':
+			for r := s.peekRune(0); r != '\n' && r != -1; r = s.peekRune(0) {
+				s.nextRune()
+			}
+		case r == '/' && s.peekRune(1) == '*':
+			line, column := s.line, s.column
+			s.nextRune()
+			s.nextRune()
+			for {
+				r := s.nextRune()
+				if r == -1 {
+					return s.errorf(line, column, "unterminated comment")
+				}
+				if r == '*' && s.peekRune(0) == '/' {
+					s.nextRune()
+					break
+				}
+			}
+		default:
+			return nil
+		}
+	}
+}
+
+// next returns the next token or an error.
+func (s *scanner) next() (token, error) {
+	if err := s.skipSpace(); err != nil {
+		return token{}, err
+	}
+	tok := token{line: s.line, column: s.column}
+	r := s.peekRune(0)
+	switch {
+	case r == -1:
+		tok.kind = tokenEOF
+		return tok, nil
+	case r == '{':
+		tok.kind = tokenLeftBrace
+	case r == '}':
+		tok.kind = tokenRightBrace
+	case r == '[':
+		tok.kind = tokenLeftBracket
+	case r == ']':
+		tok.kind = tokenRightBracket
+	case r == ';':
+		tok.kind = tokenSemicolon
+	case r == ',':
+		tok.kind = tokenComma
+	case r == '=':
+		tok.kind = tokenEqual
+	case r == ':':
+		tok.kind = tokenColon
+	case r == '-' && s.peekRune(1) == '>':
+		s.nextRune()
+		tok.kind = tokenDirectedEdge
+	case r == '-' && s.peekRune(1) == '-':
+		s.nextRune()
+		tok.kind = tokenUndirectedEdge
+	case r == '"':
+		return s.quoted(tok)
+	case r == '<':
+		return s.html(tok)
+	case r == '-' || r == '.' || isDigit(r):
+		return s.numeral(tok)
+	case isLetter(r):
+		return s.identifier(tok), nil
+	default:
+		return tok, s.errorf(tok.line, tok.column, "unexpected character %q", r)
+	}
+	s.nextRune()
+	return tok, nil
+}
+
+func isLetter(r rune) bool {
+	return r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || r >= 0x80
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+func (s *scanner) identifier(tok token) token {
+	start := s.offset
+	for r := s.peekRune(0); isLetter(r) || isDigit(r); r = s.peekRune(0) {
+		s.nextRune()
+	}
+	tok.kind = tokenID
+	tok.text = s.src[start:s.offset]
+	tok.value = tok.text
+	return tok
+}
+
+func (s *scanner) numeral(tok token) (token, error) {
+	start := s.offset
+	if s.peekRune(0) == '-' {
+		s.nextRune()
+	}
+	digits, dots := 0, 0
+	for r := s.peekRune(0); isDigit(r) || r == '.'; r = s.peekRune(0) {
+		if r == '.' {
+			dots++
+		} else {
+			digits++
+		}
+		s.nextRune()
+	}
+	if digits == 0 || dots > 1 {
+		return tok, s.errorf(tok.line, tok.column, "invalid numeral %q", s.src[start:s.offset])
+	}
+	if r := s.peekRune(0); isLetter(r) {
+		return tok, s.errorf(s.line, s.column, "numeral %q must be separated from %q", s.src[start:s.offset], r)
+	}
+	tok.kind = tokenID
+	tok.text = s.src[start:s.offset]
+	tok.value = tok.text
+	return tok, nil
+}
+
+// quoted reads one or more double-quoted strings joined with '+'.
+// The value is a string if the only escape used is \" ; otherwise it is a Literal
+// that keeps the escape sequences (such as \l or \N) for Graphviz to interpret.
+func (s *scanner) quoted(tok token) (token, error) {
+	raw := new(strings.Builder)
+	for {
+		line, column := s.line, s.column
+		s.nextRune() // opening quote
+		for {
+			r := s.nextRune()
+			if r == -1 {
+				return tok, s.errorf(line, column, "unterminated string")
+			}
+			if r == '"' {
+				break
+			}
+			if r == '\\' {
+				escaped := s.peekRune(0)
+				if escaped == '\n' {
+					// line continuation
+					s.nextRune()
+					continue
+				}
+				if escaped == '\r' && s.peekRune(1) == '\n' {
+					s.nextRune()
+					s.nextRune()
+					continue
+				}
+				if escaped == '"' || escaped == '\\' {
+					s.nextRune()
+					raw.WriteRune('\\')
+					raw.WriteRune(escaped)
+					continue
+				}
+			}
+			raw.WriteRune(r)
+		}
+		// look for concatenation
+		save := *s
+		if err := s.skipSpace(); err != nil {
+			return tok, err
+		}
+		if s.peekRune(0) != '+' {
+			*s = save
+			break
+		}
+		s.nextRune()
+		if err := s.skipSpace(); err != nil {
+			return tok, err
+		}
+		if s.peekRune(0) != '"' {
+			return tok, s.errorf(s.line, s.column, "expected quoted string after '+'")
+		}
+	}
+	tok.kind = tokenID
+	tok.quoted = true
+	content := raw.String()
+	tok.text = strings.ReplaceAll(content, `\"`, `"`)
+	if strings.Contains(strings.ReplaceAll(content, `\"`, ""), `\`) {
+		tok.value = Literal(`"` + content + `"`)
+	} else {
+		tok.value = tok.text
+	}
+	return tok, nil
+}
+
+// html reads an HTML string, which is enclosed in balanced angle brackets.
+func (s *scanner) html(tok token) (token, error) {
+	s.nextRune() // opening <
+	start := s.offset
+	depth := 1
+	for {
+		r := s.nextRune()
+		switch r {
+		case -1:
+			return tok, s.errorf(tok.line, tok.column, "unterminated HTML string")
+		case '<':
+			depth++
+		case '>':
+			depth--
+		}
+		if depth == 0 {
+			break
+		}
+	}
+	tok.kind = tokenID
+	tok.text = s.src[start : s.offset-1]
+	tok.value = HTML(tok.text)
+	return tok, nil
+}
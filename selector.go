@@ -0,0 +1,448 @@
+package dot
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Selector is a parsed CSS-like selector for nodes or edges. Examples:
+//
+//	node[shape=box]              nodes with the attribute shape equal to "box"
+//	#a, #b                       the nodes with id "a" or "b"
+//	.service                     nodes with "service" in their (space separated) "class" attribute
+//	cluster_api > node           nodes directly in the cluster with key "api" (or the subgraph with key or id "cluster_api")
+//	outer [color]                nodes with a color, in subgraph "outer" or deeper
+//	edge[style!=dashed]          edges for which the style is not "dashed"
+//
+// A selector is a comma separated list of alternatives. Each alternative is a list of subgraph parts,
+// separated by ">" (child) or whitespace (descendant), followed by a part for the nodes or edges.
+// The last part may start with "node", "edge" or "*" ; the other parts start with the key or id of a subgraph (or "*").
+// A cluster also matches "cluster_" followed by its key.
+// Each part can be followed by conditions: #id, .class and [name], [name=value], [name!=value],
+// [name^=prefix], [name$=suffix] or [name*=text]. Values can be quoted with " or '.
+type Selector struct {
+	source       string
+	alternatives [][]selectorPart
+}
+
+type selectorPart struct {
+	// combinator is ">" or " " for the relation with the previous part ; empty for the first part.
+	combinator string
+	name       string
+	id         string
+	classes    []string
+	conditions []attributeCondition
+}
+
+type attributeCondition struct {
+	name, operator, value string
+}
+
+// ParseSelector returns the parsed selector or a *ParseError.
+func ParseSelector(selector string) (*Selector, error) {
+	p := &selectorParser{source: selector}
+	s := &Selector{source: selector}
+	for {
+		parts, err := p.alternative()
+		if err != nil {
+			return nil, err
+		}
+		s.alternatives = append(s.alternatives, parts)
+		p.skipSpaces()
+		if p.done() {
+			return s, nil
+		}
+		if p.peek() != ',' {
+			return nil, p.errorf("unexpected %q", p.peek())
+		}
+		p.pos++
+	}
+}
+
+// MustParseSelector is like ParseSelector but panics if the selector is invalid.
+func MustParseSelector(selector string) *Selector {
+	s, err := ParseSelector(selector)
+	if err != nil {
+		panic(fmt.Sprintf("dot: invalid selector %q: %v", selector, err))
+	}
+	return s
+}
+
+// String returns the source of the selector.
+func (s *Selector) String() string {
+	return s.source
+}
+
+// Select returns the nodes of this graph and its subgraphs that match the selector, in order of creation.
+// Panics if the selector is invalid ; use ParseSelector for selectors that are not constants.
+func (g *Graph) Select(selector string) NodeSet {
+	return MustParseSelector(selector).Nodes(g)
+}
+
+// SelectEdges returns the edges of this graph and its subgraphs that match the selector, in order of creation.
+// Panics if the selector is invalid ; use ParseSelector for selectors that are not constants.
+func (g *Graph) SelectEdges(selector string) EdgeSet {
+	return MustParseSelector(selector).Edges(g)
+}
+
+// Nodes returns the nodes of the graph and its subgraphs that match, in order of creation.
+// Alternatives with a last part that starts with "edge" match no nodes.
+func (s *Selector) Nodes(g *Graph) NodeSet {
+	found := NodeSet{}
+	g.visitScopes(func(each *Graph) {
+		for _, n := range each.nodes {
+			if s.matches(n.attributes, n.id, n.graph, "node") {
+				found = append(found, n)
+			}
+		}
+	})
+	sort.Slice(found, func(i, j int) bool { return found[i].seq < found[j].seq })
+	return found
+}
+
+// Edges returns the edges of the graph and its subgraphs that match, in order of creation.
+// Edges are matched by the (sub)graph in which they are stored ; see Graph.Edge.
+// Alternatives with a last part that starts with "node" match no edges.
+func (s *Selector) Edges(g *Graph) EdgeSet {
+	found := EdgeSet{}
+	root := g.Root()
+	nodes := root.FindNodes()
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].seq < nodes[j].seq })
+	for _, n := range nodes {
+		for _, e := range root.outIndex[n.seq] {
+			if commonParentOf(e.graph, g) == g && s.matches(e.attributes, "", e.graph, "edge") {
+				found = append(found, e)
+			}
+		}
+	}
+	return found
+}
+
+func (s *Selector) matches(attributes map[string]interface{}, id string, scope *Graph, kind string) bool {
+	for _, parts := range s.alternatives {
+		last := parts[len(parts)-1]
+		if last.name != "" && last.name != "*" && last.name != kind {
+			continue
+		}
+		if !last.matchesConditions(attributes, id) {
+			continue
+		}
+		if matchesScopes(parts[:len(parts)-1], last.combinator, scope) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesScopes returns whether the parts match the (sub)graph and its parents, from right to left.
+func matchesScopes(parts []selectorPart, combinator string, scope *Graph) bool {
+	if len(parts) == 0 {
+		return true
+	}
+	last := parts[len(parts)-1]
+	for each := scope; each != nil; each = each.parent {
+		if last.matchesScope(each) && matchesScopes(parts[:len(parts)-1], last.combinator, each.parent) {
+			return true
+		}
+		if combinator == ">" {
+			return false
+		}
+	}
+	return false
+}
+
+// matchesScope returns whether the (sub)graph matches by its id, its key or, for a cluster, "cluster_" and its key.
+func (p selectorPart) matchesScope(g *Graph) bool {
+	if p.name != "*" && p.name != g.id && (g.parent == nil || !p.matchesKey(g)) {
+		return false
+	}
+	return p.matchesConditions(g.attributes, g.id)
+}
+
+func (p selectorPart) matchesKey(g *Graph) bool {
+	return p.name == g.Key() || (strings.HasPrefix(g.id, "cluster") && p.name == "cluster_"+g.Key())
+}
+
+func (p selectorPart) matchesConditions(attributes map[string]interface{}, id string) bool {
+	if len(p.id) > 0 && p.id != id {
+		return false
+	}
+	classes := []string{}
+	if class, ok := attributes["class"]; ok {
+		classes = strings.Fields(fmt.Sprint(class))
+	}
+	for _, each := range p.classes {
+		if !containsString(classes, each) {
+			return false
+		}
+	}
+	for _, each := range p.conditions {
+		if !each.matches(attributes) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c attributeCondition) matches(attributes map[string]interface{}) bool {
+	v, ok := attributes[c.name]
+	if c.operator == "" {
+		return ok
+	}
+	s := ""
+	if ok {
+		s = fmt.Sprint(v)
+	}
+	switch c.operator {
+	case "=":
+		return ok && s == c.value
+	case "!=":
+		return !ok || s != c.value
+	case "^=":
+		return ok && strings.HasPrefix(s, c.value)
+	case "$=":
+		return ok && strings.HasSuffix(s, c.value)
+	case "*=":
+		return ok && strings.Contains(s, c.value)
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, each := range list {
+		if each == s {
+			return true
+		}
+	}
+	return false
+}
+
+type selectorParser struct {
+	source string
+	pos    int
+}
+
+func (p *selectorParser) done() bool { return p.pos >= len(p.source) }
+
+func (p *selectorParser) peek() byte { return p.source[p.pos] }
+
+func (p *selectorParser) skipSpaces() bool {
+	start := p.pos
+	for !p.done() && strings.IndexByte(" \t\n\r", p.peek()) >= 0 {
+		p.pos++
+	}
+	return p.pos > start
+}
+
+func (p *selectorParser) errorf(format string, args ...interface{}) error {
+	return &ParseError{Line: 1, Column: p.pos + 1, Message: fmt.Sprintf(format, args...)}
+}
+
+// alternative parses the parts up to a comma or the end.
+func (p *selectorParser) alternative() ([]selectorPart, error) {
+	parts := []selectorPart{}
+	combinator := ""
+	p.skipSpaces()
+	for {
+		part, err := p.part()
+		if err != nil {
+			return nil, err
+		}
+		part.combinator = combinator
+		parts = append(parts, part)
+		spaced := p.skipSpaces()
+		if p.done() || p.peek() == ',' {
+			break
+		}
+		combinator = " "
+		if p.peek() == '>' {
+			combinator = ">"
+			p.pos++
+			p.skipSpaces()
+		} else if !spaced {
+			return nil, p.errorf("unexpected %q", p.peek())
+		}
+	}
+	if last := parts[len(parts)-1]; last.name != "" && last.name != "*" && last.name != "node" && last.name != "edge" {
+		return nil, p.errorf("the last part must select nodes or edges ; use %q for the nodes in it", last.name+" node")
+	}
+	for _, each := range parts[:len(parts)-1] {
+		if each.name == "" || each.name == "node" || each.name == "edge" {
+			return nil, p.errorf("only the last part can select nodes or edges ; start other parts with a subgraph key, id or '*'")
+		}
+	}
+	return parts, nil
+}
+
+func (p *selectorParser) part() (selectorPart, error) {
+	part := selectorPart{}
+	if !p.done() && p.peek() == '*' {
+		part.name = "*"
+		p.pos++
+	} else {
+		part.name = p.identifier()
+	}
+	for !p.done() {
+		switch p.peek() {
+		case '#':
+			p.pos++
+			id, err := p.value()
+			if err != nil {
+				return part, err
+			}
+			part.id = id
+		case '.':
+			p.pos++
+			class := p.identifier()
+			if class == "" {
+				return part, p.errorf("expected a class name")
+			}
+			part.classes = append(part.classes, class)
+		case '[':
+			p.pos++
+			condition, err := p.condition()
+			if err != nil {
+				return part, err
+			}
+			part.conditions = append(part.conditions, condition)
+		default:
+			if part.name == "" && part.id == "" && len(part.classes)+len(part.conditions) == 0 {
+				return part, p.errorf("unexpected %q", p.peek())
+			}
+			return part, nil
+		}
+	}
+	if part.name == "" && part.id == "" && len(part.classes)+len(part.conditions) == 0 {
+		return part, p.errorf("expected a selector")
+	}
+	return part, nil
+}
+
+// condition parses the text after "[" up to and including "]".
+func (p *selectorParser) condition() (attributeCondition, error) {
+	c := attributeCondition{}
+	p.skipSpaces()
+	c.name = p.identifier()
+	if c.name == "" {
+		return c, p.errorf("expected an attribute name")
+	}
+	p.skipSpaces()
+	for _, each := range []string{"=", "!=", "^=", "$=", "*="} {
+		if strings.HasPrefix(p.source[p.pos:], each) {
+			c.operator = each
+			p.pos += len(each)
+			p.skipSpaces()
+			value, err := p.value()
+			if err != nil {
+				return c, err
+			}
+			c.value = value
+			p.skipSpaces()
+			break
+		}
+	}
+	if p.done() || p.peek() != ']' {
+		return c, p.errorf("expected ']'")
+	}
+	p.pos++
+	return c, nil
+}
+
+// value parses a quoted string or an identifier.
+func (p *selectorParser) value() (string, error) {
+	if !p.done() && (p.peek() == '"' || p.peek() == '\'') {
+		quote := p.peek()
+		end := strings.IndexByte(p.source[p.pos+1:], quote)
+		if end < 0 {
+			return "", p.errorf("missing closing %c", quote)
+		}
+		value := p.source[p.pos+1 : p.pos+1+end]
+		p.pos += end + 2
+		return value, nil
+	}
+	value := p.identifier()
+	if value == "" {
+		return "", p.errorf("expected a value")
+	}
+	return value, nil
+}
+
+// identifier parses letters, digits, '_' , '-' and non-ASCII characters.
+func (p *selectorParser) identifier() string {
+	start := p.pos
+	for !p.done() {
+		c := p.peek()
+		if c == '_' || c == '-' || c >= 0x80 || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') {
+			p.pos++
+			continue
+		}
+		break
+	}
+	return p.source[start:p.pos]
+}
+
+// NodeSet is a list of nodes, e.g. the result of Graph.Select, with operations on all of them.
+type NodeSet []Node
+
+// SetAttribute sets the attribute on all nodes.
+func (s NodeSet) SetAttribute(key string, value interface{}) NodeSet {
+	for _, each := range s {
+		each.SetAttribute(key, value)
+	}
+	return s
+}
+
+// DeleteAttribute removes the attribute from all nodes.
+func (s NodeSet) DeleteAttribute(key string) NodeSet {
+	for _, each := range s {
+		each.Delete(key)
+	}
+	return s
+}
+
+// Delete removes all nodes, with their edges, from their graph.
+// Exactly the selected nodes are removed, also if nodes of other subgraphs have the same id.
+func (s NodeSet) Delete() {
+	for _, each := range s {
+		root := each.graph.Root()
+		if n, ok := root.stored(each); ok {
+			root.deleteNode(n)
+		}
+	}
+}
+
+// IDs returns the ids of the nodes.
+func (s NodeSet) IDs() []string {
+	ids := make([]string, len(s))
+	for i, each := range s {
+		ids[i] = each.id
+	}
+	return ids
+}
+
+// EdgeSet is a list of edges, e.g. the result of Graph.SelectEdges, with operations on all of them.
+type EdgeSet []Edge
+
+// SetAttribute sets the attribute on all edges.
+func (s EdgeSet) SetAttribute(key string, value interface{}) EdgeSet {
+	for _, each := range s {
+		each.SetAttribute(key, value)
+	}
+	return s
+}
+
+// DeleteAttribute removes the attribute from all edges.
+func (s EdgeSet) DeleteAttribute(key string) EdgeSet {
+	for _, each := range s {
+		each.Delete(key)
+	}
+	return s
+}
+
+// Delete removes all edges from their graph.
+func (s EdgeSet) Delete() {
+	for _, each := range s {
+		each.graph.Root().DeleteEdge(each)
+	}
+}
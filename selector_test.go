@@ -0,0 +1,106 @@
+package dot
+
+import (
+	"strings"
+	"testing"
+)
+
+func selectorGraph() *Graph {
+	di := NewGraph(Directed, NodeIDOption{})
+	api := di.Subgraph("api", ClusterOption{})
+	inner := api.Subgraph("inner")
+	di.Node("a").Box()
+	api.Node("b").Box().SetAttribute("class", "service critical")
+	api.Node("c").SetAttribute("class", "service")
+	inner.Node("d").Box()
+	e := api.Node("b").Edge(api.Node("c"))
+	e.SetAttribute("color", "red")
+	di.Node("a").Edge(inner.Node("d")).Dashed()
+	return di
+}
+
+func TestSelect(t *testing.T) {
+	g := selectorGraph()
+	for _, each := range []struct{ selector, want string }{
+		{"node[shape=box]", "a b d"},
+		{"api > node[shape=box]", "b"},
+		{"cluster_s1 > [shape=box]", "b"},
+		{"cluster_api > node[shape=box]", "b"},
+		{"cluster_inner node", ""},
+		{"api [shape=box]", "b d"},
+		{"api > * > *", "d"},
+		{".service", "b c"},
+		{".service.critical", "b"},
+		{"#a, #c", "a c"},
+		{"[class]", "b c"},
+		{"[shape!=box]", "c"},
+		{`[label^="a"]`, "a"},
+		{"[label$=d], [label*='c']", "c d"},
+		{"edge", ""},
+		{"inner node, other node", "d"},
+	} {
+		if got, want := strings.Join(g.Select(each.selector).IDs(), " "), each.want; got != want {
+			t.Errorf("%s: got [%v] want [%v]", each.selector, got, want)
+		}
+	}
+}
+
+func TestSelectEdges(t *testing.T) {
+	g := selectorGraph()
+	if got, want := len(g.SelectEdges("[color=red]")), 1; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	if got, want := len(g.SelectEdges("api > edge")), 1; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	sub, _ := g.FindSubgraph("api")
+	if got, want := len(sub.SelectEdges("*")), 1; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	g.SelectEdges("edge[style=dashed]").SetAttribute("color", "blue")
+	if got, want := len(g.SelectEdges("[color=blue]")), 1; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	g.SelectEdges("*").Delete()
+	if got, want := len(g.SelectEdges("*")), 0; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestNodeSetOperations(t *testing.T) {
+	g := selectorGraph()
+	g.Select(".service").SetAttribute("color", "green").DeleteAttribute("class")
+	if got, want := strings.Join(g.Select("[color=green]").IDs(), " "), "b c"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+	g.Select("api node").Delete()
+	if got, want := flatten(g.String()), `digraph  {subgraph cluster_s1 {subgraph s2 {label="inner";}label="api";}a[label="a",shape="box"];}`; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestNodeSetDeleteDuplicateIDs(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		g := NewGraph(Directed, NodeIDOption{})
+		one := g.Subgraph("one")
+		two := g.Subgraph("two")
+		one.Node("x").Edge(two.Node("x"))
+		two.Node("y")
+		g.Select("two #x").Delete()
+		if got, want := flatten(g.String()), `digraph  {subgraph s1 {label="one";x[label="x"];}subgraph s2 {label="two";y[label="y"];}}`; got != want {
+			t.Fatalf("got [%v] want [%v]", got, want)
+		}
+	}
+}
+
+func TestParseSelectorErrors(t *testing.T) {
+	for _, each := range []string{"", "node >", "node > api", "api", "[shape", "[=box]", "#", `[label="a]`, "a ! b", ".", "node,"} {
+		if _, err := ParseSelector(each); err == nil {
+			t.Errorf("%q: expected error", each)
+		}
+	}
+	_, err := ParseSelector("[shape")
+	if got, want := err.Error(), "line 1, column 7: expected ']'"; got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}